@@ -39,10 +39,14 @@ func fromOpcode(op opcode.Opcode) (inst, error) {
 		return new(instruction.Return), nil
 	case opcode.OpcodeCall:
 		return new(instruction.Call), nil
+	case opcode.OpcodeCallIndirect:
+		return new(instruction.CallIndirect), nil
 	case opcode.OpcodeDrop:
 		return new(instruction.Drop), nil
 	case opcode.OpcodeSelect:
 		return new(instruction.Select), nil
+	case opcode.OpcodeTypedSelect:
+		return new(instruction.TypedSelect), nil
 	case opcode.OpcodeLocalGet:
 		return new(instruction.LocalGet), nil
 	case opcode.OpcodeLocalSet:
@@ -51,6 +55,10 @@ func fromOpcode(op opcode.Opcode) (inst, error) {
 		return new(instruction.GlobalGet), nil
 	case opcode.OpcodeGlobalSet:
 		return new(instruction.GlobalSet), nil
+	case opcode.OpcodeTableGet:
+		return new(instruction.TableGet), nil
+	case opcode.OpcodeTableSet:
+		return new(instruction.TableSet), nil
 	case opcode.OpcodeI32Load:
 		return new(instruction.I32Load), nil
 	case opcode.OpcodeI64Load:
@@ -97,6 +105,10 @@ func fromOpcode(op opcode.Opcode) (inst, error) {
 		return new(instruction.I64Store16), nil
 	case opcode.OpcodeI64Store32:
 		return new(instruction.I64Store32), nil
+	case opcode.OpcodeMemorySize:
+		return new(instruction.MemorySize), nil
+	case opcode.OpcodeMemoryGrow:
+		return new(instruction.MemoryGrow), nil
 	case opcode.OpcodeI32Const:
 		return new(instruction.I32Const), nil
 	case opcode.OpcodeI64Const:
@@ -173,10 +185,200 @@ func fromOpcode(op opcode.Opcode) (inst, error) {
 		return new(instruction.F64Le), nil
 	case opcode.OpcodeF64Ge:
 		return new(instruction.F64Ge), nil
+	case opcode.OpcodeI32Clz:
+		return new(instruction.I32Clz), nil
+	case opcode.OpcodeI32Ctz:
+		return new(instruction.I32Ctz), nil
+	case opcode.OpcodeI32Popcnt:
+		return new(instruction.I32Popcnt), nil
 	case opcode.OpcodeI32Add:
 		return new(instruction.I32Add), nil
 	case opcode.OpcodeI32Sub:
 		return new(instruction.I32Sub), nil
+	case opcode.OpcodeI32Mul:
+		return new(instruction.I32Mul), nil
+	case opcode.OpcodeI32DivS:
+		return new(instruction.I32DivS), nil
+	case opcode.OpcodeI32DivU:
+		return new(instruction.I32DivU), nil
+	case opcode.OpcodeI32RemS:
+		return new(instruction.I32RemS), nil
+	case opcode.OpcodeI32RemU:
+		return new(instruction.I32RemU), nil
+	case opcode.OpcodeI32And:
+		return new(instruction.I32And), nil
+	case opcode.OpcodeI32Or:
+		return new(instruction.I32Or), nil
+	case opcode.OpcodeI32Xor:
+		return new(instruction.I32Xor), nil
+	case opcode.OpcodeI32Shl:
+		return new(instruction.I32Shl), nil
+	case opcode.OpcodeI32ShrS:
+		return new(instruction.I32ShrS), nil
+	case opcode.OpcodeI32ShrU:
+		return new(instruction.I32ShrU), nil
+	case opcode.OpcodeI32Rotl:
+		return new(instruction.I32Rotl), nil
+	case opcode.OpcodeI32Rotr:
+		return new(instruction.I32Rotr), nil
+	case opcode.OpcodeI64Clz:
+		return new(instruction.I64Clz), nil
+	case opcode.OpcodeI64Ctz:
+		return new(instruction.I64Ctz), nil
+	case opcode.OpcodeI64Popcnt:
+		return new(instruction.I64Popcnt), nil
+	case opcode.OpcodeI64Add:
+		return new(instruction.I64Add), nil
+	case opcode.OpcodeI64Sub:
+		return new(instruction.I64Sub), nil
+	case opcode.OpcodeI64Mul:
+		return new(instruction.I64Mul), nil
+	case opcode.OpcodeI64DivS:
+		return new(instruction.I64DivS), nil
+	case opcode.OpcodeI64DivU:
+		return new(instruction.I64DivU), nil
+	case opcode.OpcodeI64RemS:
+		return new(instruction.I64RemS), nil
+	case opcode.OpcodeI64RemU:
+		return new(instruction.I64RemU), nil
+	case opcode.OpcodeI64And:
+		return new(instruction.I64And), nil
+	case opcode.OpcodeI64Or:
+		return new(instruction.I64Or), nil
+	case opcode.OpcodeI64Xor:
+		return new(instruction.I64Xor), nil
+	case opcode.OpcodeI64Shl:
+		return new(instruction.I64Shl), nil
+	case opcode.OpcodeI64ShrS:
+		return new(instruction.I64ShrS), nil
+	case opcode.OpcodeI64ShrU:
+		return new(instruction.I64ShrU), nil
+	case opcode.OpcodeI64Rotl:
+		return new(instruction.I64Rotl), nil
+	case opcode.OpcodeI64Rotr:
+		return new(instruction.I64Rotr), nil
+	case opcode.OpcodeF32Abs:
+		return new(instruction.F32Abs), nil
+	case opcode.OpcodeF32Neg:
+		return new(instruction.F32Neg), nil
+	case opcode.OpcodeF32Ceil:
+		return new(instruction.F32Ceil), nil
+	case opcode.OpcodeF32Floor:
+		return new(instruction.F32Floor), nil
+	case opcode.OpcodeF32Trunc:
+		return new(instruction.F32Trunc), nil
+	case opcode.OpcodeF32Nearest:
+		return new(instruction.F32Nearest), nil
+	case opcode.OpcodeF32Sqrt:
+		return new(instruction.F32Sqrt), nil
+	case opcode.OpcodeF32Add:
+		return new(instruction.F32Add), nil
+	case opcode.OpcodeF32Sub:
+		return new(instruction.F32Sub), nil
+	case opcode.OpcodeF32Mul:
+		return new(instruction.F32Mul), nil
+	case opcode.OpcodeF32Div:
+		return new(instruction.F32Div), nil
+	case opcode.OpcodeF32Min:
+		return new(instruction.F32Min), nil
+	case opcode.OpcodeF32Max:
+		return new(instruction.F32Max), nil
+	case opcode.OpcodeF32Copysign:
+		return new(instruction.F32Copysign), nil
+	case opcode.OpcodeF64Abs:
+		return new(instruction.F64Abs), nil
+	case opcode.OpcodeF64Neg:
+		return new(instruction.F64Neg), nil
+	case opcode.OpcodeF64Ceil:
+		return new(instruction.F64Ceil), nil
+	case opcode.OpcodeF64Floor:
+		return new(instruction.F64Floor), nil
+	case opcode.OpcodeF64Trunc:
+		return new(instruction.F64Trunc), nil
+	case opcode.OpcodeF64Nearest:
+		return new(instruction.F64Nearest), nil
+	case opcode.OpcodeF64Sqrt:
+		return new(instruction.F64Sqrt), nil
+	case opcode.OpcodeF64Add:
+		return new(instruction.F64Add), nil
+	case opcode.OpcodeF64Sub:
+		return new(instruction.F64Sub), nil
+	case opcode.OpcodeF64Mul:
+		return new(instruction.F64Mul), nil
+	case opcode.OpcodeF64Div:
+		return new(instruction.F64Div), nil
+	case opcode.OpcodeF64Min:
+		return new(instruction.F64Min), nil
+	case opcode.OpcodeF64Max:
+		return new(instruction.F64Max), nil
+	case opcode.OpcodeF64Copysign:
+		return new(instruction.F64Copysign), nil
+	case opcode.OpcodeI32WrapI64:
+		return new(instruction.I32WrapI64), nil
+	case opcode.OpcodeI32TruncF32S:
+		return new(instruction.I32TruncF32S), nil
+	case opcode.OpcodeI32TruncF32U:
+		return new(instruction.I32TruncF32U), nil
+	case opcode.OpcodeI32TruncF64S:
+		return new(instruction.I32TruncF64S), nil
+	case opcode.OpcodeI32TruncF64U:
+		return new(instruction.I32TruncF64U), nil
+	case opcode.OpcodeI64ExtendI32S:
+		return new(instruction.I64ExtendSI32), nil
+	case opcode.OpcodeI64ExtendI32U:
+		return new(instruction.I64ExtendUI32), nil
+	case opcode.OpcodeI64TruncF32S:
+		return new(instruction.I64TruncF32S), nil
+	case opcode.OpcodeI64TruncF32U:
+		return new(instruction.I64TruncF32U), nil
+	case opcode.OpcodeI64TruncF64S:
+		return new(instruction.I64TruncF64S), nil
+	case opcode.OpcodeI64TruncF64U:
+		return new(instruction.I64TruncF64U), nil
+	case opcode.OpcodeF32ConvertI32S:
+		return new(instruction.F32ConvertI32S), nil
+	case opcode.OpcodeF32ConvertI32U:
+		return new(instruction.F32ConvertI32U), nil
+	case opcode.OpcodeF32ConvertI64S:
+		return new(instruction.F32ConvertI64S), nil
+	case opcode.OpcodeF32ConvertI64U:
+		return new(instruction.F32ConvertI64U), nil
+	case opcode.OpcodeF32DemoteF64:
+		return new(instruction.F32DemoteF64), nil
+	case opcode.OpcodeF64ConvertI32S:
+		return new(instruction.F64ConvertI32S), nil
+	case opcode.OpcodeF64ConvertI32U:
+		return new(instruction.F64ConvertI32U), nil
+	case opcode.OpcodeF64ConvertI64S:
+		return new(instruction.F64ConvertI64S), nil
+	case opcode.OpcodeF64ConvertI64U:
+		return new(instruction.F64ConvertI64U), nil
+	case opcode.OpcodeF64PromoteF32:
+		return new(instruction.F64PromoteF32), nil
+	case opcode.OpcodeI32ReinterpretF32:
+		return new(instruction.I32ReinterpretF32), nil
+	case opcode.OpcodeI64ReinterpretF64:
+		return new(instruction.I64ReinterpretF64), nil
+	case opcode.OpcodeF32ReinterpretI32:
+		return new(instruction.F32ReinterpretI32), nil
+	case opcode.OpcodeF64ReinterpretI64:
+		return new(instruction.F64ReinterpretI64), nil
+	case opcode.OpcodeI32Extend8S:
+		return new(instruction.I32Extend8S), nil
+	case opcode.OpcodeI32Extend16S:
+		return new(instruction.I32Extend16S), nil
+	case opcode.OpcodeI64Extend8S:
+		return new(instruction.I64Extend8S), nil
+	case opcode.OpcodeI64Extend16S:
+		return new(instruction.I64Extend16S), nil
+	case opcode.OpcodeI64Extend32S:
+		return new(instruction.I64Extend32S), nil
+	case opcode.OpcodeRefNull:
+		return new(instruction.RefNull), nil
+	case opcode.OpcodeRefIsNull:
+		return new(instruction.RefIsNull), nil
+	case opcode.OpcodeRefFunc:
+		return new(instruction.RefFunc), nil
 	case opcode.OpcodeFCPrefix:
 		return new(instruction.FCPrefix), nil
 	default:
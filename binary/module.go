@@ -22,7 +22,9 @@ type Module struct {
 	importSection   []binary.Import
 	tableSection    []binary.TableType
 	globalSection   []binary.Global
+	elementSection  []binary.Element
 	startSection    *uint32
+	dataCount       *uint32
 }
 
 func NewModule(r io.Reader) (*Module, error) {
@@ -38,6 +40,19 @@ func (m *Module) CodeSection() []binary.Function   { return m.codeSection }
 func (m *Module) ExportSection() []binary.Export   { return m.exportSection }
 func (m *Module) ImportSection() []binary.Import   { return m.importSection }
 func (m *Module) GlobalSection() []binary.Global   { return m.globalSection }
+func (m *Module) ElementSection() []binary.Element { return m.elementSection }
+func (m *Module) Start() (uint32, bool) {
+	if m.startSection == nil {
+		return 0, false
+	}
+	return *m.startSection, true
+}
+func (m *Module) DataCount() (uint32, bool) {
+	if m.dataCount == nil {
+		return 0, false
+	}
+	return *m.dataCount, true
+}
 
 func decode(r io.Reader) (*Module, error) {
 	var (
@@ -108,7 +123,10 @@ func decode(r io.Reader) (*Module, error) {
 				return nil, fmt.Errorf("failed to decode start section: %w", err)
 			}
 		case SectionCodeElement:
-			// TODO
+			module.elementSection, err = decodeElementSection(sectionContents)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode element section: %w", err)
+			}
 		case SectionCodeCode:
 			module.codeSection, err = decodeCodeSection(sectionContents)
 			if err != nil {
@@ -120,12 +138,19 @@ func decode(r io.Reader) (*Module, error) {
 				return nil, fmt.Errorf("failed to decode data section: %w", err)
 			}
 		case SectionCodeDataCount:
-			// TODO
+			module.dataCount, err = decodeDataCountSection(sectionContents)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode data count section: %w", err)
+			}
 		default:
 			return nil, fmt.Errorf("unsupported section code: %d", code)
 		}
 	}
 
+	if module.dataCount != nil && int(*module.dataCount) != len(module.dataSection) {
+		return nil, fmt.Errorf("data count and data section have inconsistent lengths: %d != %d", *module.dataCount, len(module.dataSection))
+	}
+
 	return module, nil
 }
 
@@ -162,7 +187,7 @@ func decodeSectionHeader(r io.Reader) (SectionCode, uint32, error) {
 }
 
 func decodeTypeSection(r io.Reader) ([]binary.FuncType, error) {
-	count, err := leb128.Uint32(r)
+	count, err := readCount(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read type section count: %w", err)
 	}
@@ -177,7 +202,7 @@ func decodeTypeSection(r io.Reader) ([]binary.FuncType, error) {
 			return nil, fmt.Errorf("unsupported function type: %2x", f)
 		}
 
-		paramCount, err := leb128.Uint32(r)
+		paramCount, err := readCount(r)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read parameter count: %w", err)
 		}
@@ -191,7 +216,7 @@ func decodeTypeSection(r io.Reader) ([]binary.FuncType, error) {
 			params = append(params, v)
 		}
 
-		resultCount, err := leb128.Uint32(r)
+		resultCount, err := readCount(r)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read result count: %w", err)
 		}
@@ -212,7 +237,7 @@ func decodeTypeSection(r io.Reader) ([]binary.FuncType, error) {
 }
 
 func decodeFunctionSection(r io.Reader) ([]uint32, error) {
-	count, err := leb128.Uint32(r)
+	count, err := readCount(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read function count: %w", err)
 	}
@@ -231,7 +256,7 @@ func decodeFunctionSection(r io.Reader) ([]uint32, error) {
 }
 
 func decodeCodeSection(r io.Reader) ([]binary.Function, error) {
-	count, err := leb128.Uint32(r)
+	count, err := readCount(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read function count: %w", err)
 	}
@@ -266,7 +291,7 @@ func decodeValueType(r io.Reader) (binary.ValueType, error) {
 }
 
 func decodeFunctionBody(r io.Reader) (binary.Function, error) {
-	count, err := leb128.Uint32(r)
+	count, err := readCount(r)
 	if err != nil {
 		return binary.Function{}, fmt.Errorf("failed to read local count: %w", err)
 	}
@@ -319,7 +344,7 @@ func decodeInstructions(r io.Reader) ([]binary.Instruction, error) {
 }
 
 func decodeExportSection(r io.Reader) ([]binary.Export, error) {
-	count, err := leb128.Uint32(r)
+	count, err := readCount(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read export count: %w", err)
 	}
@@ -358,7 +383,7 @@ func decodeExportSection(r io.Reader) ([]binary.Export, error) {
 }
 
 func decodeImportSection(r io.Reader) ([]binary.Import, error) {
-	count, err := leb128.Uint32(r)
+	count, err := readCount(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read import count: %w", err)
 	}
@@ -385,6 +410,24 @@ func decodeImportSection(r io.Reader) ([]binary.Import, error) {
 				return nil, fmt.Errorf("failed to read import index: %w", err)
 			}
 			imports = append(imports, binary.Import{Module: module, Field: name, Desc: binary.ImportDescFunc{Index: index}})
+		case 0x01:
+			table, err := decodeTableType(r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode import table type: %w", err)
+			}
+			imports = append(imports, binary.Import{Module: module, Field: name, Desc: binary.ImportDescTable{Type: table}})
+		case 0x02:
+			limits, err := decodeLimits(r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode import memory limits: %w", err)
+			}
+			imports = append(imports, binary.Import{Module: module, Field: name, Desc: binary.ImportDescMemory{Memory: binary.Memory{Limits: limits}}})
+		case 0x03:
+			global, err := decodeGlobalType(r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode import global type: %w", err)
+			}
+			imports = append(imports, binary.Import{Module: module, Field: name, Desc: binary.ImportDescGlobal{Type: global}})
 		default:
 			return nil, fmt.Errorf("unsupported import kind: %x", kind)
 		}
@@ -394,7 +437,7 @@ func decodeImportSection(r io.Reader) ([]binary.Import, error) {
 }
 
 func decodeMemorySection(r io.Reader) ([]binary.Memory, error) {
-	count, err := leb128.Uint32(r)
+	count, err := readCount(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read memory count: %w", err)
 	}
@@ -433,11 +476,11 @@ func decodeLimits(r io.Reader) (binary.Limits, error) {
 		return binary.Limits{}, fmt.Errorf("failed to read max: %w", err)
 	}
 
-	return binary.Limits{Min: min, Max: max}, nil
+	return binary.Limits{Min: min, Max: max, HasMax: true}, nil
 }
 
 func decodeName(r io.Reader) (string, error) {
-	size, err := leb128.Uint32(r)
+	size, err := readCount(r)
 	if err != nil {
 		return "", fmt.Errorf("failed to read name size: %w", err)
 	}
@@ -450,53 +493,6 @@ func decodeName(r io.Reader) (string, error) {
 	return string(name), nil
 }
 
-func decodeExprValue(r io.Reader) (binary.ExprValue, error) {
-	b, err := readByte(r)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read expr opcode: %w", err)
-	}
-	var value binary.ExprValue
-	switch opcode.Opcode(b) {
-	case opcode.OpcodeI32Const:
-		v, err := leb128.Int32(r)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read i32.const value: %w", err)
-		}
-		value = binary.ExprValueConstI32(v)
-	case opcode.OpcodeI64Const:
-		v, err := leb128.Int64(r)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read i64.const value: %w", err)
-		}
-		value = binary.ExprValueConstI64(v)
-	case opcode.OpcodeF32Const:
-		v, err := readF32(r)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read f32.const value: %w", err)
-		}
-		value = binary.ExprValueConstF32(v)
-	case opcode.OpcodeF64Const:
-		v, err := readF64(r)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read f64.const value: %w", err)
-		}
-		value = binary.ExprValueConstF64(v)
-	default:
-		return nil, fmt.Errorf("unsupported expr opcode: %v", opcode.Opcode(b))
-	}
-
-	b, err = readByte(r)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read expr end opcode: %w", err)
-	}
-
-	if opcode.Opcode(b) != opcode.OpcodeEnd {
-		return nil, fmt.Errorf("invalid expr end opcode: %v", opcode.Opcode(b))
-	}
-
-	return value, nil
-}
-
 func decodeExpr(r io.Reader) (binary.Expr, error) {
 	b, err := readByte(r)
 	if err != nil {
@@ -534,6 +530,18 @@ func decodeExpr(r io.Reader) (binary.Expr, error) {
 			return nil, fmt.Errorf("failed to read global index: %w", err)
 		}
 		value = binary.ExprGlobalIndex(v)
+	case opcode.OpcodeRefNull:
+		t, err := decodeRefType(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode ref.null type: %w", err)
+		}
+		value = binary.ExprRefNull(t)
+	case opcode.OpcodeRefFunc:
+		v, err := leb128.Uint32(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read function index: %w", err)
+		}
+		value = binary.ExprRefFunc(v)
 	default:
 		return nil, fmt.Errorf("unsupported expr opcode: %v", opcode.Opcode(b))
 	}
@@ -551,7 +559,7 @@ func decodeExpr(r io.Reader) (binary.Expr, error) {
 }
 
 func decodeDataSection(r io.Reader) ([]binary.Data, error) {
-	count, err := leb128.Uint32(r)
+	count, err := readCount(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read data count: %w", err)
 	}
@@ -569,7 +577,7 @@ func decodeDataSection(r io.Reader) ([]binary.Data, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to decode data offset: %w", err)
 			}
-			size, err := leb128.Uint32(r)
+			size, err := readCount(r)
 			if err != nil {
 				return nil, fmt.Errorf("failed to read data size: %w", err)
 			}
@@ -579,7 +587,7 @@ func decodeDataSection(r io.Reader) ([]binary.Data, error) {
 			}
 			data = append(data, binary.Data{Mode: binary.DataModeActive, MemoryIndex: 0, Offset: offset, Init: init})
 		case 1:
-			size, err := leb128.Uint32(r)
+			size, err := readCount(r)
 			if err != nil {
 				return nil, fmt.Errorf("failed to read data size: %w", err)
 			}
@@ -597,7 +605,7 @@ func decodeDataSection(r io.Reader) ([]binary.Data, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to decode data offset: %w", err)
 			}
-			size, err := leb128.Uint32(r)
+			size, err := readCount(r)
 			if err != nil {
 				return nil, fmt.Errorf("failed to read data size: %w", err)
 			}
@@ -615,7 +623,7 @@ func decodeDataSection(r io.Reader) ([]binary.Data, error) {
 }
 
 func decodeTableSection(r io.Reader) ([]binary.TableType, error) {
-	count, err := leb128.Uint32(r)
+	count, err := readCount(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read table count: %w", err)
 	}
@@ -623,22 +631,30 @@ func decodeTableSection(r io.Reader) ([]binary.TableType, error) {
 	tables := make([]binary.TableType, 0, count)
 
 	for range count {
-		typ, err := readByte(r)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read element type: %w", err)
-		}
-		lim, err := decodeLimits(r)
+		table, err := decodeTableType(r)
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode table limits: %w", err)
+			return nil, err
 		}
-		tables = append(tables, binary.TableType{ElementType: binary.RefType(typ), Limits: lim})
+		tables = append(tables, table)
 	}
 
 	return tables, nil
 }
 
+func decodeTableType(r io.Reader) (binary.TableType, error) {
+	typ, err := readByte(r)
+	if err != nil {
+		return binary.TableType{}, fmt.Errorf("failed to read element type: %w", err)
+	}
+	lim, err := decodeLimits(r)
+	if err != nil {
+		return binary.TableType{}, fmt.Errorf("failed to decode table limits: %w", err)
+	}
+	return binary.TableType{ElementType: binary.RefType(typ), Limits: lim}, nil
+}
+
 func decodeGlobalSection(r io.Reader) ([]binary.Global, error) {
-	count, err := leb128.Uint32(r)
+	count, err := readCount(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read global count: %w", err)
 	}
@@ -651,7 +667,7 @@ func decodeGlobalSection(r io.Reader) ([]binary.Global, error) {
 			return nil, fmt.Errorf("failed to decode global type: %w", err)
 		}
 
-		initExpr, err := decodeExprValue(r)
+		initExpr, err := decodeExpr(r)
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode global init expr: %w", err)
 		}
@@ -684,3 +700,143 @@ func decodeStartSection(r io.Reader) (*uint32, error) {
 	}
 	return &idx, nil
 }
+
+func decodeDataCountSection(r io.Reader) (*uint32, error) {
+	count, err := leb128.Uint32(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data count: %w", err)
+	}
+	return &count, nil
+}
+
+func decodeRefType(r io.Reader) (binary.RefType, error) {
+	b, err := readByte(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read reference type: %w", err)
+	}
+	switch t := binary.RefType(b); t {
+	case binary.RefTypeFunc, binary.RefTypeExtern:
+		return t, nil
+	default:
+		return 0, fmt.Errorf("unsupported reference type: %2x", b)
+	}
+}
+
+func decodeElementKind(r io.Reader) (binary.RefType, error) {
+	kind, err := readByte(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read element kind: %w", err)
+	}
+	if kind != 0x00 {
+		return 0, fmt.Errorf("unsupported element kind: %2x", kind)
+	}
+	return binary.RefTypeFunc, nil
+}
+
+func decodeElementFuncIndexes(r io.Reader) ([]binary.Expr, error) {
+	count, err := readCount(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read function index count: %w", err)
+	}
+
+	init := make([]binary.Expr, 0, count)
+	for range count {
+		idx, err := leb128.Uint32(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read function index: %w", err)
+		}
+		init = append(init, binary.ExprRefFunc(idx))
+	}
+
+	return init, nil
+}
+
+func decodeElementExprs(r io.Reader) ([]binary.Expr, error) {
+	count, err := readCount(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read element expression count: %w", err)
+	}
+
+	init := make([]binary.Expr, 0, count)
+	for range count {
+		expr, err := decodeExpr(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode element expression: %w", err)
+		}
+		init = append(init, expr)
+	}
+
+	return init, nil
+}
+
+func decodeElementSection(r io.Reader) ([]binary.Element, error) {
+	count, err := readCount(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read element count: %w", err)
+	}
+
+	elements := make([]binary.Element, 0, count)
+
+	for range count {
+		flags, err := leb128.Uint32(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read element flags: %w", err)
+		}
+		if flags > 7 {
+			return nil, fmt.Errorf("unsupported element flags: %d", flags)
+		}
+
+		// bit 0: passive or declarative, bit 1: explicit table index (active) or declarative, bit 2: expressions
+		var (
+			elem        binary.Element
+			passive     = flags&0x01 != 0
+			explicit    = flags&0x02 != 0
+			expressions = flags&0x04 != 0
+		)
+
+		switch {
+		case !passive:
+			elem.Mode = binary.ElementModeActive
+			if explicit {
+				elem.TableIndex, err = leb128.Uint32(r)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read table index: %w", err)
+				}
+			}
+			elem.Offset, err = decodeExpr(r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode element offset: %w", err)
+			}
+		case explicit:
+			elem.Mode = binary.ElementModeDeclarative
+		default:
+			elem.Mode = binary.ElementModePassive
+		}
+
+		// flags 0 and 4 have neither element kind nor reference type and always hold funcref
+		switch {
+		case !passive && !explicit:
+			elem.Type = binary.RefTypeFunc
+		case expressions:
+			elem.Type, err = decodeRefType(r)
+		default:
+			elem.Type, err = decodeElementKind(r)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if expressions {
+			elem.Init, err = decodeElementExprs(r)
+		} else {
+			elem.Init, err = decodeElementFuncIndexes(r)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, elem)
+	}
+
+	return elements, nil
+}
@@ -303,6 +303,33 @@ func TestDecodeImport(t *testing.T) {
 	}
 }
 
+func TestDecodeImportKinds(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/import_all.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	got, err := NewModule(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to parse wasm: %v", err)
+		t.FailNow()
+	}
+
+	want := []binary.Import{
+		{Module: "env", Field: "memory", Desc: binary.ImportDescMemory{Memory: binary.Memory{Limits: binary.Limits{Min: 1}}}},
+		{Module: "env", Field: "table", Desc: binary.ImportDescTable{Type: binary.TableType{ElementType: binary.RefTypeFunc, Limits: binary.Limits{Min: 2}}}},
+		{Module: "env", Field: "counter", Desc: binary.ImportDescGlobal{Type: binary.GlobalType{ValueType: binary.ValueTypeI32, Mutable: true}}},
+		{Module: "env", Field: "base", Desc: binary.ImportDescGlobal{Type: binary.GlobalType{ValueType: binary.ValueTypeI32}}},
+	}
+
+	if !reflect.DeepEqual(want, got.ImportSection()) {
+		t.Errorf("unexpected imports: %#v", got.ImportSection())
+	}
+}
+
 func TestDecodeFib(t *testing.T) {
 	t.Parallel()
 
@@ -364,3 +391,127 @@ func TestDecodeFib(t *testing.T) {
 		t.Errorf("unexpected Module: %#v", got)
 	}
 }
+
+func TestDecodeElement(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/table.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	got, err := NewModule(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to parse wasm: %v", err)
+		t.FailNow()
+	}
+
+	want := []binary.Element{
+		{
+			Mode:       binary.ElementModeActive,
+			TableIndex: 0,
+			Offset:     binary.ExprValueConstI32(1),
+			Type:       binary.RefTypeFunc,
+			Init:       []binary.Expr{binary.ExprRefFunc(0), binary.ExprRefFunc(1)},
+		},
+		{
+			Mode:       binary.ElementModeActive,
+			TableIndex: 1,
+			Offset:     binary.ExprValueConstI32(0),
+			Type:       binary.RefTypeFunc,
+			Init:       []binary.Expr{binary.ExprRefFunc(2)},
+		},
+		{
+			Mode:       binary.ElementModeActive,
+			TableIndex: 0,
+			Offset:     binary.ExprValueConstI32(3),
+			Type:       binary.RefTypeFunc,
+			Init:       []binary.Expr{binary.ExprRefFunc(2), binary.ExprRefNull(binary.RefTypeFunc)},
+		},
+		{
+			Mode: binary.ElementModePassive,
+			Type: binary.RefTypeFunc,
+			Init: []binary.Expr{binary.ExprRefFunc(0)},
+		},
+		{
+			Mode: binary.ElementModeDeclarative,
+			Type: binary.RefTypeFunc,
+			Init: []binary.Expr{binary.ExprRefFunc(1)},
+		},
+	}
+
+	if !reflect.DeepEqual(want, got.ElementSection()) {
+		t.Errorf("unexpected element section: %#v", got.ElementSection())
+	}
+}
+
+func TestDecodeDataCount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		file   string
+		count  uint32
+		exists bool
+	}{
+		{file: "../testdata/memory.wasm"},
+		{file: "../testdata/memory_bulk.wasm", count: 2, exists: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			b, err := os.ReadFile(test.file)
+			if err != nil {
+				t.Errorf("failed to load testdata: %v", err)
+				t.FailNow()
+			}
+
+			got, err := NewModule(bytes.NewReader(b))
+			if err != nil {
+				t.Errorf("failed to parse wasm: %v", err)
+				t.FailNow()
+			}
+
+			count, ok := got.DataCount()
+			if count != test.count || ok != test.exists {
+				t.Errorf("unexpected data count: %d, %v", count, ok)
+			}
+		})
+	}
+}
+
+func TestDecodeHugeCount(t *testing.T) {
+	t.Parallel()
+
+	const preamble = "\x00asm\x01\x00\x00\x00"
+	// huge is 0xFFFFFFFF as LEB128.
+	const huge = "\xff\xff\xff\xff\x0f"
+
+	tests := []struct {
+		name string
+		wasm string
+	}{
+		{name: "section size", wasm: preamble + "\x01" + huge},
+		{name: "type count", wasm: preamble + "\x01\x05" + huge},
+		{name: "param count", wasm: preamble + "\x01\x07\x01\x60" + huge},
+		{name: "function count", wasm: preamble + "\x03\x05" + huge},
+		{name: "local count", wasm: preamble + "\x01\x04\x01\x60\x00\x00\x03\x02\x01\x00\x0a\x08\x01\x06" + huge + "\x0b"},
+		{name: "br_table label count", wasm: preamble + "\x01\x04\x01\x60\x00\x00\x03\x02\x01\x00\x0a\x09\x01\x07\x00\x0e" + huge},
+		{name: "name size", wasm: preamble + "\x02\x06\x01" + huge},
+		{name: "data size", wasm: preamble + "\x0b\x0a\x01\x00\x41\x00\x0b" + huge},
+		{name: "element count", wasm: preamble + "\x09\x05" + huge},
+		{name: "element function index count", wasm: preamble + "\x09\x0a\x01\x00\x41\x00\x0b" + huge},
+		{name: "element expression count", wasm: preamble + "\x09\x0a\x01\x04\x41\x00\x0b" + huge},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// the count is rejected instead of allocating for it.
+			if _, err := NewModule(bytes.NewReader([]byte(test.wasm))); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+
+	"github.com/Warashi/wasmium/leb128"
 )
 
 var endian = binary.LittleEndian
@@ -15,14 +17,33 @@ type ints interface {
 
 func take[T ints](n T) func(r io.Reader) (io.Reader, error) {
 	return func(r io.Reader) (io.Reader, error) {
-		b := make([]byte, int(n))
-		if _, err := io.ReadFull(r, b); err != nil {
+		// NOTE: n comes from the module, so the buffer grows with the bytes actually read
+		// instead of being allocated up front.
+		b, err := io.ReadAll(io.LimitReader(r, int64(n)))
+		if err != nil {
 			return nil, fmt.Errorf("failed to read %d bytes: %w", n, err)
 		}
+		if uint64(len(b)) < uint64(n) {
+			return nil, fmt.Errorf("failed to read %d bytes: %w", n, io.ErrUnexpectedEOF)
+		}
 		return bytes.NewReader(b), nil
 	}
 }
 
+// readCount reads the length of a vector.
+// Every element of a vector takes at least one byte, so a length larger than the rest of r
+// is rejected before anything is allocated for it.
+func readCount(r io.Reader) (uint32, error) {
+	count, err := leb128.Uint32(r)
+	if err != nil {
+		return 0, err
+	}
+	if rest, ok := r.(interface{ Len() int }); ok && uint64(rest.Len()) < uint64(count) {
+		return 0, fmt.Errorf("length %d exceeds the remaining %d bytes", count, rest.Len())
+	}
+	return count, nil
+}
+
 func readByte(r io.Reader) (byte, error) {
 	var (
 		b [1]byte
@@ -1,8 +1,11 @@
 package instruction
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"math"
+	"slices"
 
 	"github.com/Warashi/wasmium/leb128"
 	"github.com/Warashi/wasmium/opcode"
@@ -20,57 +23,85 @@ func decodeBlock(r io.Reader) (binary.Block, error) {
 	switch buf[0] {
 	case 0x40:
 		return binary.Block{BlockType: binary.BlockTypeVoid{}}, nil
-	default:
+	case byte(binary.ValueTypeI32), byte(binary.ValueTypeI64), byte(binary.ValueTypeF32), byte(binary.ValueTypeF64),
+		byte(binary.ValueTypeFuncRef), byte(binary.ValueTypeExternRef):
 		return binary.Block{BlockType: binary.BlockTypeValue{ValueTypes: []binary.ValueType{binary.ValueType(buf[0])}}}, nil
 	}
+
+	// NOTE: otherwise the block type is a type index encoded as s33, whose first byte is already consumed.
+	index, err := leb128.Int64(io.MultiReader(bytes.NewReader(buf[:]), r))
+	if err != nil {
+		return binary.Block{}, fmt.Errorf("failed to read block type index: %w", err)
+	}
+	if index < 0 || math.MaxUint32 < index {
+		return binary.Block{}, fmt.Errorf("invalid block type index: %d", index)
+	}
+
+	return binary.Block{BlockType: binary.BlockTypeIndex{Index: uint32(index)}}, nil
 }
 
-func br(r runtime.Runtime, f *runtime.Frame, level uint32) (int, error) {
+// enterBlock pushes a label for a block taking params values from the stack.
+func enterBlock(r runtime.Runtime, f *runtime.Frame, params int) error {
+	sp := r.StackLen() - params
+	if sp < f.StackPointer {
+		return fmt.Errorf("stack underflow")
+	}
+
+	f.Labels.Push(runtime.NewLabel(sp))
+
+	return nil
+}
+
+// br branches to the label at level and returns the address to continue after.
+func br(r runtime.Runtime, f *runtime.Frame, level uint32, target runtime.BranchTarget) (int, error) {
+	if target.Return {
+		return f.ProgramCounter, ret(r)
+	}
+
 	index := f.Labels.Len() - 1 - int(level)
+	if index < 0 {
+		return 0, fmt.Errorf("invalid branch depth: %d", level)
+	}
 	label := f.Labels[index]
 
-	if label.Kind() == runtime.LabelKindLoop {
-		// NOTE: we still need loop label to jump to the beginning of the loop.
+	if target.Loop {
+		// NOTE: the loop label is kept since the branch re-enters the loop.
 		f.Labels.Drain(index + 1)
-
-		// NOTE: since it jumps to the beginning of the loop,
-		// the stack is unwound without considering the return value.
-		if err := r.StackUnwind(label.StackPointer(), 0); err != nil {
-			return 0, fmt.Errorf("failed to unwind stack: %w", err)
-		}
-
-		return label.Start(), nil
+	} else {
+		f.Labels.Drain(index)
 	}
-	f.Labels.Drain(index)
-	if err := r.StackUnwind(label.StackPointer(), label.Arity()); err != nil {
+	if err := r.StackUnwind(label.StackPointer(), target.Arity); err != nil {
 		return 0, fmt.Errorf("failed to unwind stack: %w", err)
 	}
-	return label.ProgramCounter(), nil
+
+	return target.ProgramCounter, nil
 }
 
-func getEndAddress(insts []runtime.Instruction, programCounter int) (int, error) {
-	depth := 0
-	for {
-		programCounter++
-		if programCounter < 0 || len(insts) <= programCounter {
-			return 0, fmt.Errorf("unexpected end of instructions")
+func call(r runtime.Runtime, funcInst runtime.FuncInst) error {
+	switch f := funcInst.(type) {
+	case runtime.InternalFuncInst:
+		if f.Instance == nil || f.Instance == r {
+			return r.PushFrame(f)
 		}
-
-		switch insts[programCounter].(type) {
-		case *If:
-			depth++
-		case *Block:
-			depth++
-		case *Loop:
-			depth++
-		case *End:
-			if depth == 0 {
-				return programCounter, nil
-			}
-			depth--
-		default:
-			// do nothing
+		v, err := r.InvokeInternal(f)
+		if err != nil {
+			return fmt.Errorf("failed to invoke function of another instance: %w", err)
 		}
+		for _, v := range v {
+			r.PushStack(v)
+		}
+		return nil
+	case runtime.ExternalFuncInst:
+		v, err := r.InvokeExternal(f)
+		if err != nil {
+			return fmt.Errorf("failed to invoke external function: %w", err)
+		}
+		for _, v := range v {
+			r.PushStack(v)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected function instance: %T", f)
 	}
 }
 
@@ -81,7 +112,7 @@ func (*Unreachable) Opcode() opcode.Opcode { return opcode.OpcodeUnreachable }
 func (*Unreachable) ReadOperandsFrom(io.Reader) error { return nil }
 
 func (*Unreachable) Execute(runtime.Runtime, *runtime.Frame) error {
-	return fmt.Errorf("unreachable")
+	return runtime.ErrUnreachable
 }
 
 type Nop struct{}
@@ -96,6 +127,9 @@ func (*Nop) Execute(runtime.Runtime, *runtime.Frame) error {
 
 type Block struct {
 	Block binary.Block
+
+	// params is resolved by instruction.Convert.
+	params int
 }
 
 func (*Block) Opcode() opcode.Opcode { return opcode.OpcodeBlock }
@@ -106,18 +140,21 @@ func (b *Block) ReadOperandsFrom(r io.Reader) error {
 	return err
 }
 
+func (b *Block) BlockType() binary.BlockType { return b.Block.BlockType }
+
+func (b *Block) Resolve(params, _, _, _ int) {
+	b.params = params
+}
+
 func (b *Block) Execute(r runtime.Runtime, f *runtime.Frame) error {
-	arity := b.Block.BlockType.ResultCount()
-	pc, err := getEndAddress(f.Instructions, f.ProgramCounter)
-	if err != nil {
-		return fmt.Errorf("failed to get end address: %w", err)
-	}
-	f.Labels.Push(runtime.NewLabel(runtime.LabelKindBlock, 0, pc, r.StackLen(), arity))
-	return nil
+	return enterBlock(r, f, b.params)
 }
 
 type Loop struct {
 	Block binary.Block
+
+	// params is resolved by instruction.Convert.
+	params int
 }
 
 func (*Loop) Opcode() opcode.Opcode { return opcode.OpcodeLoop }
@@ -127,21 +164,24 @@ func (l *Loop) ReadOperandsFrom(r io.Reader) error {
 	return err
 }
 
-func (l *Loop) Execute(r runtime.Runtime, f *runtime.Frame) error {
-	arity := l.Block.BlockType.ResultCount()
-	startProgramCounter := f.ProgramCounter
-	programCounter, err := getEndAddress(f.Instructions, f.ProgramCounter)
-	if err != nil {
-		return fmt.Errorf("failed to get end address: %w", err)
-	}
+func (l *Loop) BlockType() binary.BlockType { return l.Block.BlockType }
 
-	f.Labels.Push(runtime.NewLabel(runtime.LabelKindLoop, startProgramCounter, programCounter, r.StackLen(), arity))
+func (l *Loop) Resolve(params, _, _, _ int) {
+	l.params = params
+}
 
-	return nil
+func (l *Loop) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return enterBlock(r, f, l.params)
 }
 
 type If struct {
 	Block binary.Block
+
+	// params, elseAddress and end are resolved by instruction.Convert.
+	// elseAddress is -1 if the if has no else.
+	params      int
+	elseAddress int
+	end         int
 }
 
 func (*If) Opcode() opcode.Opcode { return opcode.OpcodeIf }
@@ -150,54 +190,79 @@ func (i *If) ReadOperandsFrom(r io.Reader) error {
 	i.Block, err = decodeBlock(r)
 	return err
 }
+func (i *If) BlockType() binary.BlockType { return i.Block.BlockType }
+
+func (i *If) Resolve(params, _, elseAddress, end int) {
+	i.params, i.elseAddress, i.end = params, elseAddress, end
+}
+
 func (i *If) Execute(r runtime.Runtime, f *runtime.Frame) error {
-	cond, err := r.PopStack()
+	cond, err := popValue[runtime.ValueI32](r)
 	if err != nil {
-		return fmt.Errorf("failed to pop stack: %w", err)
+		return err
 	}
 
-	nextProgramCounter, err := getEndAddress(f.Instructions, f.ProgramCounter)
-	if err != nil {
-		return fmt.Errorf("failed to get end address: %w", err)
+	if err := enterBlock(r, f, i.params); err != nil {
+		return err
 	}
 
-	if !cond.Bool() {
-		f.ProgramCounter = nextProgramCounter
+	if cond == 0 {
+		if i.elseAddress < 0 {
+			// NOTE: execute the matching End so that the label is popped.
+			f.ProgramCounter = i.end - 1
+		} else {
+			f.ProgramCounter = i.elseAddress
+		}
 	}
 
-	f.Labels.Push(runtime.NewLabel(runtime.LabelKindIf, 0, nextProgramCounter, r.StackLen(), i.Block.BlockType.ResultCount()))
-
 	return nil
 }
 
-type Else struct{}
+type Else struct {
+	// results and end are resolved by instruction.Convert.
+	results int
+	end     int
+}
 
 func (*Else) Opcode() opcode.Opcode { return opcode.OpcodeElse }
 
 func (*Else) ReadOperandsFrom(io.Reader) error { return nil }
 
-func (*Else) Execute(r runtime.Runtime, f *runtime.Frame) error {
-	label := f.Labels.Pop()
-	if label.Kind() != runtime.LabelKindIf {
-		return fmt.Errorf("unexpected label kind: %d", label.Kind())
+func (e *Else) Resolve(results, end int) {
+	e.results, e.end = results, end
+}
+
+// Execute finishes the then branch, skipping the else branch and the matching End.
+func (e *Else) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	label, ok := f.Labels.Pop()
+	if !ok {
+		return fmt.Errorf("else without if: %w", runtime.ErrEmptyStack)
 	}
 
-	f.ProgramCounter = label.ProgramCounter()
+	f.ProgramCounter = e.end
+	if err := r.StackUnwind(label.StackPointer(), e.results); err != nil {
+		return fmt.Errorf("failed to unwind stack: %w", err)
+	}
 
 	return nil
 }
 
-type End struct{}
+type End struct {
+	// results is resolved by instruction.Convert.
+	results int
+}
 
 func (*End) Opcode() opcode.Opcode { return opcode.OpcodeEnd }
 
 func (*End) ReadOperandsFrom(io.Reader) error { return nil }
 
-func (*End) Execute(r runtime.Runtime, f *runtime.Frame) error {
-	if f.Labels.Len() > 0 {
-		label := f.Labels.Pop()
-		f.ProgramCounter = label.ProgramCounter()
-		if err := r.StackUnwind(label.StackPointer(), label.Arity()); err != nil {
+func (e *End) Resolve(results, _ int) {
+	e.results = results
+}
+
+func (e *End) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	if label, ok := f.Labels.Pop(); ok {
+		if err := r.StackUnwind(label.StackPointer(), e.results); err != nil {
 			return fmt.Errorf("failed to unwind stack: %w", err)
 		}
 	} else {
@@ -215,6 +280,9 @@ func (*End) Execute(r runtime.Runtime, f *runtime.Frame) error {
 
 type Br struct {
 	Level uint32
+
+	// target is resolved by instruction.Convert.
+	target runtime.BranchTarget
 }
 
 func (*Br) Opcode() opcode.Opcode { return opcode.OpcodeBr }
@@ -225,14 +293,23 @@ func (b *Br) ReadOperandsFrom(r io.Reader) error {
 	return err
 }
 
+func (b *Br) Labels() []uint32 { return []uint32{b.Level} }
+
+func (b *Br) Resolve(targets []runtime.BranchTarget) {
+	b.target = targets[0]
+}
+
 func (b *Br) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	var err error
-	f.ProgramCounter, err = br(r, f, b.Level)
+	f.ProgramCounter, err = br(r, f, b.Level, b.target)
 	return err
 }
 
 type BrIf struct {
 	Level uint32
+
+	// target is resolved by instruction.Convert.
+	target runtime.BranchTarget
 }
 
 func (*BrIf) Opcode() opcode.Opcode { return opcode.OpcodeBrIf }
@@ -243,23 +320,32 @@ func (b *BrIf) ReadOperandsFrom(r io.Reader) error {
 	return err
 }
 
+func (b *BrIf) Labels() []uint32 { return []uint32{b.Level} }
+
+func (b *BrIf) Resolve(targets []runtime.BranchTarget) {
+	b.target = targets[0]
+}
+
 func (b *BrIf) Execute(r runtime.Runtime, f *runtime.Frame) error {
-	cond, err := r.PopStack()
+	cond, err := popValue[runtime.ValueI32](r)
 	if err != nil {
-		return fmt.Errorf("failed to pop stack: %w", err)
+		return err
 	}
 
-	if !cond.Bool() {
+	if cond == 0 {
 		return nil
 	}
 
-	f.ProgramCounter, err = br(r, f, b.Level)
+	f.ProgramCounter, err = br(r, f, b.Level, b.target)
 	return err
 }
 
 type BrTable struct {
 	Levels  []uint32
 	Default uint32
+
+	// targets are resolved by instruction.Convert, followed by the target of Default.
+	targets []runtime.BranchTarget
 }
 
 func (*BrTable) Opcode() opcode.Opcode { return opcode.OpcodeBrTable }
@@ -271,7 +357,8 @@ func (b *BrTable) ReadOperandsFrom(r io.Reader) error {
 		return fmt.Errorf("failed to read count: %w", err)
 	}
 
-	b.Levels = make([]uint32, 0, count)
+	// NOTE: count comes from the module, so Levels grows as the levels are read
+	// instead of being allocated up front.
 	for range count {
 		level, err := leb128.Uint32(r)
 		if err != nil {
@@ -284,25 +371,31 @@ func (b *BrTable) ReadOperandsFrom(r io.Reader) error {
 	return err
 }
 
+func (b *BrTable) Labels() []uint32 {
+	return append(slices.Clone(b.Levels), b.Default)
+}
+
+func (b *BrTable) Resolve(targets []runtime.BranchTarget) {
+	b.targets = targets
+}
+
 func (b *BrTable) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	cond, err := r.PopStack()
 	if err != nil {
 		return fmt.Errorf("failed to pop stack: %w", err)
 	}
 
-	var level uint32
 	index, ok := cond.(runtime.ValueI32)
 	if !ok {
 		return fmt.Errorf("invalid value(%T): %w", cond, runtime.ErrInvalidValue)
 	}
 
-	if index < 0 || len(b.Levels) <= int(index) {
-		level = b.Default
-	} else {
-		level = b.Levels[index]
+	level, target := b.Default, b.targets[len(b.Levels)]
+	if 0 <= index && int(index) < len(b.Levels) {
+		level, target = b.Levels[index], b.targets[index]
 	}
 
-	f.ProgramCounter, err = br(r, f, level)
+	f.ProgramCounter, err = br(r, f, level, target)
 	return err
 }
 
@@ -313,6 +406,10 @@ func (*Return) Opcode() opcode.Opcode { return opcode.OpcodeReturn }
 func (*Return) ReadOperandsFrom(io.Reader) error { return nil }
 
 func (*Return) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return ret(r)
+}
+
+func ret(r runtime.Runtime) error {
 	frame, err := r.PopCallStack()
 	if err != nil {
 		return fmt.Errorf("failed to pop call stack: %w", err)
@@ -341,19 +438,76 @@ func (c *Call) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	if err != nil {
 		return fmt.Errorf("failed to get function: %w", err)
 	}
-	switch f := funcInst.(type) {
+	return call(r, funcInst)
+}
+
+type CallIndirect struct {
+	TypeIndex  uint32
+	TableIndex uint32
+}
+
+func (c *CallIndirect) Opcode() opcode.Opcode { return opcode.OpcodeCallIndirect }
+
+func (c *CallIndirect) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	c.TypeIndex, err = leb128.Uint32(r)
+	if err != nil {
+		return fmt.Errorf("failed to read type index: %w", err)
+	}
+	c.TableIndex, err = leb128.Uint32(r)
+	if err != nil {
+		return fmt.Errorf("failed to read table index: %w", err)
+	}
+	return nil
+}
+
+func (c *CallIndirect) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	v, err := r.PopStack()
+	if err != nil {
+		return fmt.Errorf("failed to pop stack: %w", err)
+	}
+
+	index, ok := v.(runtime.ValueI32)
+	if !ok {
+		return fmt.Errorf("invalid value(%T): %w", v, runtime.ErrInvalidValue)
+	}
+
+	table, err := r.Table(int(c.TableIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get table: %w", err)
+	}
+
+	if len(table.Elements) <= int(uint32(index)) {
+		return runtime.ErrUndefinedElement
+	}
+
+	ref, ok := table.Elements[uint32(index)].(runtime.ValueFuncRef)
+	if !ok {
+		return fmt.Errorf("invalid element(%T): %w", table.Elements[uint32(index)], runtime.ErrInvalidValue)
+	}
+	if ref.IsNull() {
+		return runtime.ErrUninitializedElement
+	}
+	funcInst := ref.Func
+
+	expected, err := r.FuncType(int(c.TypeIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get function type: %w", err)
+	}
+
+	var actual binary.FuncType
+	switch funcInst := funcInst.(type) {
 	case runtime.InternalFuncInst:
-		return r.PushFrame(f)
+		actual = funcInst.FuncType
 	case runtime.ExternalFuncInst:
-		v, err := r.InvokeExternal(f)
-		if err != nil {
-			return fmt.Errorf("failed to invoke external function: %w", err)
-		}
-		for _, v := range v {
-			r.PushStack(v)
-		}
+		actual = funcInst.FuncType
 	}
-	return nil
+
+	if !expected.Equal(actual) {
+		return runtime.ErrIndirectCallTypeMismatch
+	}
+
+	return call(r, funcInst)
 }
 
 type Drop struct{}
@@ -374,9 +528,9 @@ func (*Select) Opcode() opcode.Opcode { return opcode.OpcodeSelect }
 func (*Select) ReadOperandsFrom(io.Reader) error { return nil }
 
 func (*Select) Execute(r runtime.Runtime, f *runtime.Frame) error {
-	cond, err := r.PopStack()
+	cond, err := popValue[runtime.ValueI32](r)
 	if err != nil {
-		return fmt.Errorf("failed to pop stack: %w", err)
+		return err
 	}
 
 	v2, err := r.PopStack()
@@ -389,7 +543,7 @@ func (*Select) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		return fmt.Errorf("failed to pop stack: %w", err)
 	}
 
-	if cond.Bool() {
+	if cond != 0 {
 		r.PushStack(v1)
 	} else {
 		r.PushStack(v2)
@@ -397,3 +551,29 @@ func (*Select) Execute(r runtime.Runtime, f *runtime.Frame) error {
 
 	return nil
 }
+
+// TypedSelect is select with explicit result types, required for reference operands.
+type TypedSelect struct {
+	Select
+	Types []binary.ValueType
+}
+
+func (*TypedSelect) Opcode() opcode.Opcode { return opcode.OpcodeTypedSelect }
+
+func (s *TypedSelect) ReadOperandsFrom(r io.Reader) error {
+	count, err := leb128.Uint32(r)
+	if err != nil {
+		return fmt.Errorf("failed to read count: %w", err)
+	}
+
+	// NOTE: Types grows as the types are read, as count comes from the module.
+	for range count {
+		var buf [1]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return fmt.Errorf("failed to read value type: %w", err)
+		}
+		s.Types = append(s.Types, binary.ValueType(buf[0]))
+	}
+
+	return nil
+}
@@ -45,6 +45,26 @@ func (i *FCPrefix) ReadOperandsFrom(r io.Reader) error {
 		i.FC = new(FCI64TruncSatF64S)
 	case opcode.OpcodeFCI64TruncSatF64U:
 		i.FC = new(FCI64TruncSatF64U)
+	case opcode.OpcodeFCMemoryInit:
+		i.FC = new(FCMemoryInit)
+	case opcode.OpcodeFCDataDrop:
+		i.FC = new(FCDataDrop)
+	case opcode.OpcodeFCMemoryCopy:
+		i.FC = new(FCMemoryCopy)
+	case opcode.OpcodeFCMemoryFill:
+		i.FC = new(FCMemoryFill)
+	case opcode.OpcodeFCTableInit:
+		i.FC = new(FCTableInit)
+	case opcode.OpcodeFCElemDrop:
+		i.FC = new(FCElemDrop)
+	case opcode.OpcodeFCTableCopy:
+		i.FC = new(FCTableCopy)
+	case opcode.OpcodeFCTableGrow:
+		i.FC = new(FCTableGrow)
+	case opcode.OpcodeFCTableSize:
+		i.FC = new(FCTableSize)
+	case opcode.OpcodeFCTableFill:
+		i.FC = new(FCTableFill)
 	default:
 		return fmt.Errorf("unknown FC opcode: %v", b)
 	}
@@ -108,7 +128,7 @@ func (*FCI32TruncSatF32U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	} else if fv < 0 {
 		r.PushStack(runtime.ValueI32(0))
 	} else if fv > math.MaxUint32 {
-		r.PushStack(runtime.ValueI32(math.MaxInt32))
+		r.PushStack(runtime.ValueI32(-1)) // math.MaxUint32
 	} else {
 		r.PushStack(runtime.ValueI32(uint32(fv)))
 	}
@@ -172,7 +192,7 @@ func (*FCI32TruncSatF64U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	} else if fv < 0 {
 		r.PushStack(runtime.ValueI32(0))
 	} else if fv > math.MaxUint32 {
-		r.PushStack(runtime.ValueI32(math.MaxInt32))
+		r.PushStack(runtime.ValueI32(-1)) // math.MaxUint32
 	} else {
 		r.PushStack(runtime.ValueI32(uint32(fv)))
 	}
@@ -203,7 +223,7 @@ func (*FCI64TruncSatF32S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		r.PushStack(runtime.ValueI64(0))
 	} else if fv < math.MinInt64 {
 		r.PushStack(runtime.ValueI64(math.MinInt64))
-	} else if fv > math.MaxInt64 {
+	} else if fv >= math.MaxInt64 {
 		r.PushStack(runtime.ValueI64(math.MaxInt64))
 	} else {
 		r.PushStack(runtime.ValueI64(int64(fv)))
@@ -235,8 +255,8 @@ func (*FCI64TruncSatF32U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		r.PushStack(runtime.ValueI64(0))
 	} else if fv < 0 {
 		r.PushStack(runtime.ValueI64(0))
-	} else if fv > math.MaxUint64 {
-		r.PushStack(runtime.ValueI64(math.MaxInt64))
+	} else if fv >= math.MaxUint64 {
+		r.PushStack(runtime.ValueI64(-1)) // math.MaxUint64
 	} else {
 		r.PushStack(runtime.ValueI64(uint64(fv)))
 	}
@@ -267,7 +287,7 @@ func (*FCI64TruncSatF64S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		r.PushStack(runtime.ValueI64(0))
 	} else if fv < math.MinInt64 {
 		r.PushStack(runtime.ValueI64(math.MinInt64))
-	} else if fv > math.MaxInt64 {
+	} else if fv >= math.MaxInt64 {
 		r.PushStack(runtime.ValueI64(math.MaxInt64))
 	} else {
 		r.PushStack(runtime.ValueI64(int64(fv)))
@@ -299,8 +319,8 @@ func (*FCI64TruncSatF64U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		r.PushStack(runtime.ValueI64(0))
 	} else if fv < 0 {
 		r.PushStack(runtime.ValueI64(0))
-	} else if fv > math.MaxUint64 {
-		r.PushStack(runtime.ValueI64(math.MaxInt64))
+	} else if fv >= math.MaxUint64 {
+		r.PushStack(runtime.ValueI64(-1)) // math.MaxUint64
 	} else {
 		r.PushStack(runtime.ValueI64(uint64(fv)))
 	}
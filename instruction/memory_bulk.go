@@ -0,0 +1,179 @@
+package instruction
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Warashi/wasmium/leb128"
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type FCMemoryInit struct {
+	DataIndex   uint32
+	MemoryIndex uint32
+}
+
+func (*FCMemoryInit) Opcode() opcode.OpcodeFC { return opcode.OpcodeFCMemoryInit }
+
+func (i *FCMemoryInit) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.DataIndex, err = leb128.Uint32(r)
+	if err != nil {
+		return err
+	}
+	i.MemoryIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *FCMemoryInit) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	d, s, n, err := popBulkOperands(r)
+	if err != nil {
+		return err
+	}
+
+	memory, err := r.Memory(int(i.MemoryIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get memory: %w", err)
+	}
+
+	data, err := r.Data(int(i.DataIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get data: %w", err)
+	}
+
+	if !inBounds(s, n, len(data.Data)) || !inBounds(d, n, len(memory.Data)) {
+		return runtime.ErrMemoryOutOfBounds
+	}
+
+	copy(memory.Data[d:d+n], data.Data[s:s+n])
+
+	return nil
+}
+
+type FCDataDrop struct {
+	DataIndex uint32
+}
+
+func (*FCDataDrop) Opcode() opcode.OpcodeFC { return opcode.OpcodeFCDataDrop }
+
+func (i *FCDataDrop) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.DataIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *FCDataDrop) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	data, err := r.Data(int(i.DataIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get data: %w", err)
+	}
+
+	data.Data = nil
+
+	return nil
+}
+
+type FCMemoryCopy struct {
+	DstMemoryIndex uint32
+	SrcMemoryIndex uint32
+}
+
+func (*FCMemoryCopy) Opcode() opcode.OpcodeFC { return opcode.OpcodeFCMemoryCopy }
+
+func (i *FCMemoryCopy) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.DstMemoryIndex, err = leb128.Uint32(r)
+	if err != nil {
+		return err
+	}
+	i.SrcMemoryIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *FCMemoryCopy) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	d, s, n, err := popBulkOperands(r)
+	if err != nil {
+		return err
+	}
+
+	dst, err := r.Memory(int(i.DstMemoryIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get memory: %w", err)
+	}
+
+	src, err := r.Memory(int(i.SrcMemoryIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get memory: %w", err)
+	}
+
+	if !inBounds(s, n, len(src.Data)) || !inBounds(d, n, len(dst.Data)) {
+		return runtime.ErrMemoryOutOfBounds
+	}
+
+	// NOTE: copy handles overlapping regions like memmove.
+	copy(dst.Data[d:d+n], src.Data[s:s+n])
+
+	return nil
+}
+
+type FCMemoryFill struct {
+	MemoryIndex uint32
+}
+
+func (*FCMemoryFill) Opcode() opcode.OpcodeFC { return opcode.OpcodeFCMemoryFill }
+
+func (i *FCMemoryFill) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.MemoryIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *FCMemoryFill) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	d, val, n, err := popBulkOperands(r)
+	if err != nil {
+		return err
+	}
+
+	memory, err := r.Memory(int(i.MemoryIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get memory: %w", err)
+	}
+
+	if !inBounds(d, n, len(memory.Data)) {
+		return runtime.ErrMemoryOutOfBounds
+	}
+
+	region := memory.Data[d : d+n]
+	for j := range region {
+		region[j] = byte(val)
+	}
+
+	return nil
+}
+
+// popBulkOperands pops the three i32 operands shared by the bulk instructions
+// in the order they were pushed.
+func popBulkOperands(r runtime.Runtime) (a, b, c uint64, err error) {
+	vc, err := popValue[runtime.ValueI32](r)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	vb, err := popValue[runtime.ValueI32](r)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	va, err := popValue[runtime.ValueI32](r)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return uint64(uint32(va)), uint64(uint32(vb)), uint64(uint32(vc)), nil
+}
+
+// inBounds reports whether [offset, offset+n) lies within a region of the given size.
+func inBounds(offset, n uint64, size int) bool {
+	return offset+n <= uint64(size)
+}
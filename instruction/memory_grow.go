@@ -0,0 +1,45 @@
+package instruction
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Warashi/wasmium/leb128"
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type MemoryGrow struct {
+	MemoryIndex uint32
+}
+
+func (i *MemoryGrow) Opcode() opcode.Opcode {
+	return opcode.OpcodeMemoryGrow
+}
+
+func (i *MemoryGrow) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.MemoryIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *MemoryGrow) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	v, err := r.PopStack()
+	if err != nil {
+		return fmt.Errorf("failed to pop stack: %w", err)
+	}
+
+	delta, ok := v.(runtime.ValueI32)
+	if !ok {
+		return fmt.Errorf("invalid value(%T): %w", v, runtime.ErrInvalidValue)
+	}
+
+	memory, err := r.Memory(int(i.MemoryIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get memory: %w", err)
+	}
+
+	r.PushStack(runtime.ValueI32(memory.Grow(uint32(delta))))
+
+	return nil
+}
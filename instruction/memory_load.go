@@ -10,9 +10,43 @@ import (
 	"github.com/Warashi/wasmium/types/runtime"
 )
 
+// memArgMemoryIndex is set in the alignment of a memarg that is followed by a memory index.
+// Without it, the access is to memory 0.
+const memArgMemoryIndex = 1 << 6
+
+// readMemArg reads the memarg immediate of a load or store.
+func readMemArg(r io.Reader) (align, memoryIndex, offset uint32, err error) {
+	align, err = leb128.Uint32(r)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to read align: %w", err)
+	}
+
+	if align&memArgMemoryIndex != 0 {
+		align &^= memArgMemoryIndex
+		memoryIndex, err = leb128.Uint32(r)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("failed to read memory index: %w", err)
+		}
+	}
+
+	offset, err = leb128.Uint32(r)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to read offset: %w", err)
+	}
+
+	return align, memoryIndex, offset, nil
+}
+
+// effectiveAddress returns the address accessed with the offset of a memarg.
+// It is computed in 64 bits, so that an access beyond 4GiB traps instead of wrapping around.
+func effectiveAddress(addr runtime.ValueI32, offset uint32) int64 {
+	return int64(uint64(uint32(addr)) + uint64(offset))
+}
+
 type I32Load struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I32Load) Opcode() opcode.Opcode {
@@ -21,17 +55,8 @@ func (i *I32Load) Opcode() opcode.Opcode {
 
 func (i *I32Load) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I32Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -46,7 +71,7 @@ func (i *I32Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [4]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -61,8 +86,9 @@ func (i *I32Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Load struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Load) Opcode() opcode.Opcode {
@@ -71,17 +97,8 @@ func (i *I64Load) Opcode() opcode.Opcode {
 
 func (i *I64Load) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I64Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -96,7 +113,7 @@ func (i *I64Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [8]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -111,8 +128,9 @@ func (i *I64Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I32Load8S struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I32Load8S) Opcode() opcode.Opcode {
@@ -121,17 +139,8 @@ func (i *I32Load8S) Opcode() opcode.Opcode {
 
 func (i *I32Load8S) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I32Load8S) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -146,7 +155,7 @@ func (i *I32Load8S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [1]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -162,8 +171,9 @@ func (i *I32Load8S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I32Load8U struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I32Load8U) Opcode() opcode.Opcode {
@@ -172,17 +182,8 @@ func (i *I32Load8U) Opcode() opcode.Opcode {
 
 func (i *I32Load8U) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I32Load8U) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -197,7 +198,7 @@ func (i *I32Load8U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [1]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -212,8 +213,9 @@ func (i *I32Load8U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I32Load16S struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I32Load16S) Opcode() opcode.Opcode {
@@ -222,17 +224,8 @@ func (i *I32Load16S) Opcode() opcode.Opcode {
 
 func (i *I32Load16S) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I32Load16S) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -247,7 +240,7 @@ func (i *I32Load16S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [2]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -263,8 +256,9 @@ func (i *I32Load16S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I32Load16U struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I32Load16U) Opcode() opcode.Opcode {
@@ -273,17 +267,8 @@ func (i *I32Load16U) Opcode() opcode.Opcode {
 
 func (i *I32Load16U) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I32Load16U) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -298,7 +283,7 @@ func (i *I32Load16U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [2]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -313,8 +298,9 @@ func (i *I32Load16U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Load8S struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Load8S) Opcode() opcode.Opcode {
@@ -323,17 +309,8 @@ func (i *I64Load8S) Opcode() opcode.Opcode {
 
 func (i *I64Load8S) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I64Load8S) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -348,7 +325,7 @@ func (i *I64Load8S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [1]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -363,8 +340,9 @@ func (i *I64Load8S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Load8U struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Load8U) Opcode() opcode.Opcode {
@@ -373,17 +351,8 @@ func (i *I64Load8U) Opcode() opcode.Opcode {
 
 func (i *I64Load8U) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I64Load8U) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -398,7 +367,7 @@ func (i *I64Load8U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [1]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -413,8 +382,9 @@ func (i *I64Load8U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Load16S struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Load16S) Opcode() opcode.Opcode {
@@ -423,17 +393,8 @@ func (i *I64Load16S) Opcode() opcode.Opcode {
 
 func (i *I64Load16S) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I64Load16S) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -448,7 +409,7 @@ func (i *I64Load16S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [2]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -463,8 +424,9 @@ func (i *I64Load16S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Load16U struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Load16U) Opcode() opcode.Opcode {
@@ -473,17 +435,8 @@ func (i *I64Load16U) Opcode() opcode.Opcode {
 
 func (i *I64Load16U) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I64Load16U) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -498,7 +451,7 @@ func (i *I64Load16U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [2]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -513,8 +466,9 @@ func (i *I64Load16U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Load32U struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Load32U) Opcode() opcode.Opcode {
@@ -523,17 +477,8 @@ func (i *I64Load32U) Opcode() opcode.Opcode {
 
 func (i *I64Load32U) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I64Load32U) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -548,7 +493,7 @@ func (i *I64Load32U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [4]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -563,8 +508,9 @@ func (i *I64Load32U) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Load32S struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Load32S) Opcode() opcode.Opcode {
@@ -573,17 +519,8 @@ func (i *I64Load32S) Opcode() opcode.Opcode {
 
 func (i *I64Load32S) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *I64Load32S) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -598,7 +535,7 @@ func (i *I64Load32S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [4]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -613,8 +550,9 @@ func (i *I64Load32S) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type F32Load struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *F32Load) Opcode() opcode.Opcode {
@@ -623,17 +561,8 @@ func (i *F32Load) Opcode() opcode.Opcode {
 
 func (i *F32Load) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *F32Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -648,7 +577,7 @@ func (i *F32Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [4]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -658,8 +587,9 @@ func (i *F32Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type F64Load struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *F64Load) Opcode() opcode.Opcode {
@@ -668,17 +598,8 @@ func (i *F64Load) Opcode() opcode.Opcode {
 
 func (i *F64Load) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read align: %w", err)
-	}
-
-	i.Offset, err = leb128.Uint32(r)
-	if err != nil {
-		return fmt.Errorf("failed to read offset: %w", err)
-	}
-
-	return nil
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
+	return err
 }
 
 func (i *F64Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
@@ -693,7 +614,7 @@ func (i *F64Load) Execute(r runtime.Runtime, f *runtime.Frame) error {
 	}
 
 	var buf [8]byte
-	if n, err := r.ReadMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.ReadMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to read memory(%d): %w", n, err)
 	}
 
@@ -0,0 +1,35 @@
+package instruction
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Warashi/wasmium/leb128"
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type MemorySize struct {
+	MemoryIndex uint32
+}
+
+func (i *MemorySize) Opcode() opcode.Opcode {
+	return opcode.OpcodeMemorySize
+}
+
+func (i *MemorySize) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.MemoryIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *MemorySize) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	memory, err := r.Memory(int(i.MemoryIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get memory: %w", err)
+	}
+
+	r.PushStack(runtime.ValueI32(memory.Size()))
+
+	return nil
+}
@@ -5,14 +5,14 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/Warashi/wasmium/leb128"
 	"github.com/Warashi/wasmium/opcode"
 	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32Store struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I32Store) Opcode() opcode.Opcode {
@@ -21,11 +21,7 @@ func (i *I32Store) Opcode() opcode.Opcode {
 
 func (i *I32Store) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return err
-	}
-	i.Offset, err = leb128.Uint32(r)
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
 	return err
 }
 
@@ -55,7 +51,7 @@ func (i *I32Store) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		return fmt.Errorf("failed to encode value: %w", err)
 	}
 
-	if n, err := r.WriteMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.WriteMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to write memory(%d): %w", n, err)
 	}
 
@@ -63,8 +59,9 @@ func (i *I32Store) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Store struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Store) Opcode() opcode.Opcode {
@@ -73,11 +70,7 @@ func (i *I64Store) Opcode() opcode.Opcode {
 
 func (i *I64Store) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return err
-	}
-	i.Offset, err = leb128.Uint32(r)
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
 	return err
 }
 
@@ -107,7 +100,7 @@ func (i *I64Store) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		return fmt.Errorf("failed to encode value: %w", err)
 	}
 
-	if n, err := r.WriteMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.WriteMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to write memory(%d): %w", n, err)
 	}
 
@@ -115,8 +108,9 @@ func (i *I64Store) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I32Store8 struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I32Store8) Opcode() opcode.Opcode {
@@ -125,11 +119,7 @@ func (i *I32Store8) Opcode() opcode.Opcode {
 
 func (i *I32Store8) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return err
-	}
-	i.Offset, err = leb128.Uint32(r)
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
 	return err
 }
 
@@ -159,7 +149,7 @@ func (i *I32Store8) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		return fmt.Errorf("failed to encode value: %w", err)
 	}
 
-	if n, err := r.WriteMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.WriteMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to write memory(%d): %w", n, err)
 	}
 
@@ -167,8 +157,9 @@ func (i *I32Store8) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I32Store16 struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I32Store16) Opcode() opcode.Opcode {
@@ -177,11 +168,7 @@ func (i *I32Store16) Opcode() opcode.Opcode {
 
 func (i *I32Store16) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return err
-	}
-	i.Offset, err = leb128.Uint32(r)
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
 	return err
 }
 
@@ -211,7 +198,7 @@ func (i *I32Store16) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		return fmt.Errorf("failed to encode value: %w", err)
 	}
 
-	if n, err := r.WriteMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.WriteMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to write memory(%d): %w", n, err)
 	}
 
@@ -219,8 +206,9 @@ func (i *I32Store16) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Store8 struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Store8) Opcode() opcode.Opcode {
@@ -229,11 +217,7 @@ func (i *I64Store8) Opcode() opcode.Opcode {
 
 func (i *I64Store8) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return err
-	}
-	i.Offset, err = leb128.Uint32(r)
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
 	return err
 }
 
@@ -263,7 +247,7 @@ func (i *I64Store8) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		return fmt.Errorf("failed to encode value: %w", err)
 	}
 
-	if n, err := r.WriteMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.WriteMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to write memory(%d): %w", n, err)
 	}
 
@@ -271,8 +255,9 @@ func (i *I64Store8) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Store16 struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Store16) Opcode() opcode.Opcode {
@@ -281,11 +266,7 @@ func (i *I64Store16) Opcode() opcode.Opcode {
 
 func (i *I64Store16) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return err
-	}
-	i.Offset, err = leb128.Uint32(r)
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
 	return err
 }
 
@@ -315,7 +296,7 @@ func (i *I64Store16) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		return fmt.Errorf("failed to encode value: %w", err)
 	}
 
-	if n, err := r.WriteMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.WriteMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to write memory(%d): %w", n, err)
 	}
 
@@ -323,8 +304,9 @@ func (i *I64Store16) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type I64Store32 struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *I64Store32) Opcode() opcode.Opcode {
@@ -333,11 +315,7 @@ func (i *I64Store32) Opcode() opcode.Opcode {
 
 func (i *I64Store32) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return err
-	}
-	i.Offset, err = leb128.Uint32(r)
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
 	return err
 }
 
@@ -367,7 +345,7 @@ func (i *I64Store32) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		return fmt.Errorf("failed to encode value: %w", err)
 	}
 
-	if n, err := r.WriteMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.WriteMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to write memory(%d): %w", n, err)
 	}
 
@@ -375,8 +353,9 @@ func (i *I64Store32) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type F32Store struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *F32Store) Opcode() opcode.Opcode {
@@ -385,11 +364,7 @@ func (i *F32Store) Opcode() opcode.Opcode {
 
 func (i *F32Store) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return err
-	}
-	i.Offset, err = leb128.Uint32(r)
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
 	return err
 }
 
@@ -419,7 +394,7 @@ func (i *F32Store) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		return fmt.Errorf("failed to encode value: %w", err)
 	}
 
-	if n, err := r.WriteMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.WriteMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to write memory(%d): %w", n, err)
 	}
 
@@ -427,8 +402,9 @@ func (i *F32Store) Execute(r runtime.Runtime, f *runtime.Frame) error {
 }
 
 type F64Store struct {
-	Align  uint32
-	Offset uint32
+	Align       uint32
+	MemoryIndex uint32
+	Offset      uint32
 }
 
 func (i *F64Store) Opcode() opcode.Opcode {
@@ -437,11 +413,7 @@ func (i *F64Store) Opcode() opcode.Opcode {
 
 func (i *F64Store) ReadOperandsFrom(r io.Reader) error {
 	var err error
-	i.Align, err = leb128.Uint32(r)
-	if err != nil {
-		return err
-	}
-	i.Offset, err = leb128.Uint32(r)
+	i.Align, i.MemoryIndex, i.Offset, err = readMemArg(r)
 	return err
 }
 
@@ -471,7 +443,7 @@ func (i *F64Store) Execute(r runtime.Runtime, f *runtime.Frame) error {
 		return fmt.Errorf("failed to encode value: %w", err)
 	}
 
-	if n, err := r.WriteMemoryAt(0, buf[:], int64(uint32(a)+i.Offset)); err != nil || n != len(buf) {
+	if n, err := r.WriteMemoryAt(int(i.MemoryIndex), buf[:], effectiveAddress(a, i.Offset)); err != nil || n != len(buf) {
 		return fmt.Errorf("failed to write memory(%d): %w", n, err)
 	}
 
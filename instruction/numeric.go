@@ -0,0 +1,141 @@
+package instruction
+
+import (
+	"fmt"
+
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+func popValue[T runtime.Value](r runtime.Runtime) (T, error) {
+	var zero T
+
+	v, err := r.PopStack()
+	if err != nil {
+		return zero, fmt.Errorf("failed to pop stack: %w", err)
+	}
+
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("invalid value(%T): %w", v, runtime.ErrInvalidValue)
+	}
+
+	return t, nil
+}
+
+// unaryOp pops an operand of type T and pushes fn applied to it.
+func unaryOp[T runtime.Value](r runtime.Runtime, fn func(T) runtime.Value) error {
+	v, err := popValue[T](r)
+	if err != nil {
+		return err
+	}
+
+	r.PushStack(fn(v))
+
+	return nil
+}
+
+// checkedUnaryOp is unaryOp for operations which can trap.
+func checkedUnaryOp[T runtime.Value](r runtime.Runtime, fn func(T) (runtime.Value, error)) error {
+	v, err := popValue[T](r)
+	if err != nil {
+		return err
+	}
+
+	result, err := fn(v)
+	if err != nil {
+		return err
+	}
+
+	r.PushStack(result)
+
+	return nil
+}
+
+// binaryOp pops two operands of type T and pushes fn applied to them.
+// The first argument of fn is the operand pushed first.
+func binaryOp[T runtime.Value](r runtime.Runtime, fn func(a, b T) runtime.Value) error {
+	b, err := popValue[T](r)
+	if err != nil {
+		return err
+	}
+
+	a, err := popValue[T](r)
+	if err != nil {
+		return err
+	}
+
+	r.PushStack(fn(a, b))
+
+	return nil
+}
+
+// checkedBinaryOp is binaryOp for operations which can trap.
+func checkedBinaryOp[T runtime.Value](r runtime.Runtime, fn func(a, b T) (runtime.Value, error)) error {
+	b, err := popValue[T](r)
+	if err != nil {
+		return err
+	}
+
+	a, err := popValue[T](r)
+	if err != nil {
+		return err
+	}
+
+	result, err := fn(a, b)
+	if err != nil {
+		return err
+	}
+
+	r.PushStack(result)
+
+	return nil
+}
+
+func boolValue(b bool) runtime.Value {
+	if b {
+		return runtime.ValueI32(1)
+	}
+	return runtime.ValueI32(0)
+}
+
+// f32UnaryOp is unaryOp for f32 operands computed on float32.
+func f32UnaryOp(r runtime.Runtime, fn func(float32) float32) error {
+	return unaryOp(r, func(a runtime.ValueF32) runtime.Value {
+		return runtime.NewValueF32(fn(a.Float32()))
+	})
+}
+
+// f64UnaryOp is unaryOp for f64 operands computed on float64.
+func f64UnaryOp(r runtime.Runtime, fn func(float64) float64) error {
+	return unaryOp(r, func(a runtime.ValueF64) runtime.Value {
+		return runtime.NewValueF64(fn(a.Float64()))
+	})
+}
+
+// f32BinaryOp is binaryOp for f32 operands computed on float32.
+func f32BinaryOp(r runtime.Runtime, fn func(a, b float32) float32) error {
+	return binaryOp(r, func(a, b runtime.ValueF32) runtime.Value {
+		return runtime.NewValueF32(fn(a.Float32(), b.Float32()))
+	})
+}
+
+// f64BinaryOp is binaryOp for f64 operands computed on float64.
+func f64BinaryOp(r runtime.Runtime, fn func(a, b float64) float64) error {
+	return binaryOp(r, func(a, b runtime.ValueF64) runtime.Value {
+		return runtime.NewValueF64(fn(a.Float64(), b.Float64()))
+	})
+}
+
+// f32Compare pushes the result of comparing two f32 operands as an i32.
+func f32Compare(r runtime.Runtime, fn func(a, b float32) bool) error {
+	return binaryOp(r, func(a, b runtime.ValueF32) runtime.Value {
+		return boolValue(fn(a.Float32(), b.Float32()))
+	})
+}
+
+// f64Compare pushes the result of comparing two f64 operands as an i32.
+func f64Compare(r runtime.Runtime, fn func(a, b float64) bool) error {
+	return binaryOp(r, func(a, b runtime.ValueF64) runtime.Value {
+		return boolValue(fn(a.Float64(), b.Float64()))
+	})
+}
@@ -54,6 +54,12 @@ func (i *I64Add) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64Add) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return a + b
+	})
+}
+
 type F32Add struct{}
 
 func (f *F32Add) Opcode() opcode.Opcode {
@@ -64,6 +70,12 @@ func (f *F32Add) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32Add) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32BinaryOp(r, func(a, b float32) float32 {
+		return a + b
+	})
+}
+
 type F64Add struct{}
 
 func (f *F64Add) Opcode() opcode.Opcode {
@@ -73,3 +85,9 @@ func (f *F64Add) Opcode() opcode.Opcode {
 func (f *F64Add) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64Add) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64BinaryOp(r, func(a, b float64) float64 {
+		return a + b
+	})
+}
@@ -0,0 +1,105 @@
+package instruction
+
+import (
+	"io"
+	"math/bits"
+
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type I32Clz struct{}
+
+func (i *I32Clz) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32Clz
+}
+
+func (i *I32Clz) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32Clz) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.ValueI32(bits.LeadingZeros32(uint32(a)))
+	})
+}
+
+type I32Ctz struct{}
+
+func (i *I32Ctz) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32Ctz
+}
+
+func (i *I32Ctz) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32Ctz) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.ValueI32(bits.TrailingZeros32(uint32(a)))
+	})
+}
+
+type I32Popcnt struct{}
+
+func (i *I32Popcnt) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32Popcnt
+}
+
+func (i *I32Popcnt) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32Popcnt) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.ValueI32(bits.OnesCount32(uint32(a)))
+	})
+}
+
+type I64Clz struct{}
+
+func (i *I64Clz) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Clz
+}
+
+func (i *I64Clz) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Clz) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.ValueI64(bits.LeadingZeros64(uint64(a)))
+	})
+}
+
+type I64Ctz struct{}
+
+func (i *I64Ctz) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Ctz
+}
+
+func (i *I64Ctz) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Ctz) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.ValueI64(bits.TrailingZeros64(uint64(a)))
+	})
+}
+
+type I64Popcnt struct{}
+
+func (i *I64Popcnt) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Popcnt
+}
+
+func (i *I64Popcnt) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Popcnt) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.ValueI64(bits.OnesCount64(uint64(a)))
+	})
+}
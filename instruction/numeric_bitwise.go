@@ -0,0 +1,104 @@
+package instruction
+
+import (
+	"io"
+
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type I32And struct{}
+
+func (i *I32And) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32And
+}
+
+func (i *I32And) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32And) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return a & b
+	})
+}
+
+type I32Or struct{}
+
+func (i *I32Or) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32Or
+}
+
+func (i *I32Or) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32Or) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return a | b
+	})
+}
+
+type I32Xor struct{}
+
+func (i *I32Xor) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32Xor
+}
+
+func (i *I32Xor) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32Xor) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return a ^ b
+	})
+}
+
+type I64And struct{}
+
+func (i *I64And) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64And
+}
+
+func (i *I64And) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64And) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return a & b
+	})
+}
+
+type I64Or struct{}
+
+func (i *I64Or) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Or
+}
+
+func (i *I64Or) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Or) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return a | b
+	})
+}
+
+type I64Xor struct{}
+
+func (i *I64Xor) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Xor
+}
+
+func (i *I64Xor) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Xor) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return a ^ b
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type F32ConvertI32S struct{}
@@ -16,6 +17,12 @@ func (f *F32ConvertI32S) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32ConvertI32S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.NewValueF32(float32(a))
+	})
+}
+
 type F32ConvertI32U struct{}
 
 func (f *F32ConvertI32U) Opcode() opcode.Opcode {
@@ -26,6 +33,12 @@ func (f *F32ConvertI32U) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32ConvertI32U) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.NewValueF32(float32(uint32(a)))
+	})
+}
+
 type F32ConvertI64S struct{}
 
 func (f *F32ConvertI64S) Opcode() opcode.Opcode {
@@ -36,6 +49,12 @@ func (f *F32ConvertI64S) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32ConvertI64S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.NewValueF32(float32(a))
+	})
+}
+
 type F32ConvertI64U struct{}
 
 func (f *F32ConvertI64U) Opcode() opcode.Opcode {
@@ -46,6 +65,12 @@ func (f *F32ConvertI64U) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32ConvertI64U) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.NewValueF32(float32(uint64(a)))
+	})
+}
+
 type F64ConvertI32S struct{}
 
 func (f *F64ConvertI32S) Opcode() opcode.Opcode {
@@ -56,6 +81,12 @@ func (f *F64ConvertI32S) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F64ConvertI32S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.NewValueF64(float64(a))
+	})
+}
+
 type F64ConvertI32U struct{}
 
 func (f *F64ConvertI32U) Opcode() opcode.Opcode {
@@ -66,6 +97,12 @@ func (f *F64ConvertI32U) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F64ConvertI32U) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.NewValueF64(float64(uint32(a)))
+	})
+}
+
 type F64ConvertI64S struct{}
 
 func (f *F64ConvertI64S) Opcode() opcode.Opcode {
@@ -76,6 +113,12 @@ func (f *F64ConvertI64S) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F64ConvertI64S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.NewValueF64(float64(a))
+	})
+}
+
 type F64ConvertI64U struct{}
 
 func (f *F64ConvertI64U) Opcode() opcode.Opcode {
@@ -85,3 +128,9 @@ func (f *F64ConvertI64U) Opcode() opcode.Opcode {
 func (f *F64ConvertI64U) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64ConvertI64U) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.NewValueF64(float64(uint64(a)))
+	})
+}
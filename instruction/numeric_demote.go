@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type F32DemoteF64 struct{}
@@ -15,3 +16,9 @@ func (f *F32DemoteF64) Opcode() opcode.Opcode {
 func (f *F32DemoteF64) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F32DemoteF64) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueF64) runtime.Value {
+		return runtime.NewValueF32(float32(a.Float64()))
+	})
+}
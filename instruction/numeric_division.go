@@ -2,8 +2,10 @@ package instruction
 
 import (
 	"io"
+	"math"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32DivS struct{}
@@ -16,6 +18,18 @@ func (i *I32DivS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32DivS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedBinaryOp(r, func(a, b runtime.ValueI32) (runtime.Value, error) {
+		if b == 0 {
+			return nil, runtime.ErrIntegerDivideByZero
+		}
+		if a == math.MinInt32 && b == -1 {
+			return nil, runtime.ErrIntegerOverflow
+		}
+		return a / b, nil
+	})
+}
+
 type I32DivU struct{}
 
 func (i *I32DivU) Opcode() opcode.Opcode {
@@ -26,6 +40,15 @@ func (i *I32DivU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32DivU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedBinaryOp(r, func(a, b runtime.ValueI32) (runtime.Value, error) {
+		if b == 0 {
+			return nil, runtime.ErrIntegerDivideByZero
+		}
+		return runtime.ValueI32(uint32(a) / uint32(b)), nil
+	})
+}
+
 type I64DivS struct{}
 
 func (i *I64DivS) Opcode() opcode.Opcode {
@@ -36,6 +59,18 @@ func (i *I64DivS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64DivS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedBinaryOp(r, func(a, b runtime.ValueI64) (runtime.Value, error) {
+		if b == 0 {
+			return nil, runtime.ErrIntegerDivideByZero
+		}
+		if a == math.MinInt64 && b == -1 {
+			return nil, runtime.ErrIntegerOverflow
+		}
+		return a / b, nil
+	})
+}
+
 type I64DivU struct{}
 
 func (i *I64DivU) Opcode() opcode.Opcode {
@@ -46,6 +81,15 @@ func (i *I64DivU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64DivU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedBinaryOp(r, func(a, b runtime.ValueI64) (runtime.Value, error) {
+		if b == 0 {
+			return nil, runtime.ErrIntegerDivideByZero
+		}
+		return runtime.ValueI64(uint64(a) / uint64(b)), nil
+	})
+}
+
 type F32Div struct{}
 
 func (f *F32Div) Opcode() opcode.Opcode {
@@ -56,6 +100,12 @@ func (f *F32Div) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32Div) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32BinaryOp(r, func(a, b float32) float32 {
+		return a / b
+	})
+}
+
 type F64Div struct{}
 
 func (f *F64Div) Opcode() opcode.Opcode {
@@ -65,3 +115,9 @@ func (f *F64Div) Opcode() opcode.Opcode {
 func (f *F64Div) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64Div) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64BinaryOp(r, func(a, b float64) float64 {
+		return a / b
+	})
+}
@@ -159,33 +159,9 @@ func (i *F32Eq) ReadOperandsFrom(r io.Reader) error {
 }
 
 func (i *F32Eq) Execute(r runtime.Runtime, f *runtime.Frame) error {
-	b, err := r.PopStack()
-	if err != nil {
-		return err
-	}
-
-	a, err := r.PopStack()
-	if err != nil {
-		return err
-	}
-
-	va, ok := a.(runtime.ValueF32)
-	if !ok {
-		return fmt.Errorf("invalid value(%T): %w", a, runtime.ErrInvalidValue)
-	}
-
-	vb, ok := b.(runtime.ValueF32)
-	if !ok {
-		return fmt.Errorf("invalid value(%T): %w", b, runtime.ErrInvalidValue)
-	}
-
-	if va == vb {
-		r.PushStack(runtime.ValueI32(1))
-	} else {
-		r.PushStack(runtime.ValueI32(0))
-	}
-
-	return nil
+	return f32Compare(r, func(a, b float32) bool {
+		return a == b
+	})
 }
 
 type F64Eq struct{}
@@ -199,31 +175,7 @@ func (i *F64Eq) ReadOperandsFrom(r io.Reader) error {
 }
 
 func (i *F64Eq) Execute(r runtime.Runtime, f *runtime.Frame) error {
-	b, err := r.PopStack()
-	if err != nil {
-		return err
-	}
-
-	a, err := r.PopStack()
-	if err != nil {
-		return err
-	}
-
-	va, ok := a.(runtime.ValueF64)
-	if !ok {
-		return fmt.Errorf("invalid value(%T): %w", a, runtime.ErrInvalidValue)
-	}
-
-	vb, ok := b.(runtime.ValueF64)
-	if !ok {
-		return fmt.Errorf("invalid value(%T): %w", b, runtime.ErrInvalidValue)
-	}
-
-	if va == vb {
-		r.PushStack(runtime.ValueI32(1))
-	} else {
-		r.PushStack(runtime.ValueI32(0))
-	}
-
-	return nil
+	return f64Compare(r, func(a, b float64) bool {
+		return a == b
+	})
 }
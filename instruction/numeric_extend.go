@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I64ExtendSI32 struct{}
@@ -16,6 +17,12 @@ func (i *I64ExtendSI32) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64ExtendSI32) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.ValueI64(int64(a))
+	})
+}
+
 type I64ExtendUI32 struct{}
 
 func (i *I64ExtendUI32) Opcode() opcode.Opcode {
@@ -25,3 +32,89 @@ func (i *I64ExtendUI32) Opcode() opcode.Opcode {
 func (i *I64ExtendUI32) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (i *I64ExtendUI32) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.ValueI64(uint32(a))
+	})
+}
+
+type I32Extend8S struct{}
+
+func (i *I32Extend8S) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32Extend8S
+}
+
+func (i *I32Extend8S) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32Extend8S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.ValueI32(int8(a))
+	})
+}
+
+type I32Extend16S struct{}
+
+func (i *I32Extend16S) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32Extend16S
+}
+
+func (i *I32Extend16S) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32Extend16S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.ValueI32(int16(a))
+	})
+}
+
+type I64Extend8S struct{}
+
+func (i *I64Extend8S) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Extend8S
+}
+
+func (i *I64Extend8S) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Extend8S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.ValueI64(int8(a))
+	})
+}
+
+type I64Extend16S struct{}
+
+func (i *I64Extend16S) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Extend16S
+}
+
+func (i *I64Extend16S) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Extend16S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.ValueI64(int16(a))
+	})
+}
+
+type I64Extend32S struct{}
+
+func (i *I64Extend32S) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Extend32S
+}
+
+func (i *I64Extend32S) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Extend32S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.ValueI64(int32(a))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32GeS struct{}
@@ -16,6 +17,12 @@ func (i *I32GeS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32GeS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return boolValue(a >= b)
+	})
+}
+
 type I32GeU struct{}
 
 func (i *I32GeU) Opcode() opcode.Opcode {
@@ -26,6 +33,12 @@ func (i *I32GeU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32GeU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return boolValue(uint32(a) >= uint32(b))
+	})
+}
+
 type I64GeS struct{}
 
 func (i *I64GeS) Opcode() opcode.Opcode {
@@ -36,6 +49,12 @@ func (i *I64GeS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64GeS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return boolValue(a >= b)
+	})
+}
+
 type I64GeU struct{}
 
 func (i *I64GeU) Opcode() opcode.Opcode {
@@ -46,6 +65,12 @@ func (i *I64GeU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64GeU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return boolValue(uint64(a) >= uint64(b))
+	})
+}
+
 type F32Ge struct{}
 
 func (f *F32Ge) Opcode() opcode.Opcode {
@@ -56,6 +81,12 @@ func (f *F32Ge) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32Ge) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32Compare(r, func(a, b float32) bool {
+		return a >= b
+	})
+}
+
 type F64Ge struct{}
 
 func (f *F64Ge) Opcode() opcode.Opcode {
@@ -65,3 +96,9 @@ func (f *F64Ge) Opcode() opcode.Opcode {
 func (f *F64Ge) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64Ge) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64Compare(r, func(a, b float64) bool {
+		return a >= b
+	})
+}
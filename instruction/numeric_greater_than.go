@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32GtS struct{}
@@ -16,6 +17,12 @@ func (i *I32GtS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32GtS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return boolValue(a > b)
+	})
+}
+
 type I32GtU struct{}
 
 func (i *I32GtU) Opcode() opcode.Opcode {
@@ -26,6 +33,12 @@ func (i *I32GtU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32GtU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return boolValue(uint32(a) > uint32(b))
+	})
+}
+
 type I64GtS struct{}
 
 func (i *I64GtS) Opcode() opcode.Opcode {
@@ -36,6 +49,12 @@ func (i *I64GtS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64GtS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return boolValue(a > b)
+	})
+}
+
 type I64GtU struct{}
 
 func (i *I64GtU) Opcode() opcode.Opcode {
@@ -46,6 +65,12 @@ func (i *I64GtU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64GtU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return boolValue(uint64(a) > uint64(b))
+	})
+}
+
 type F32Gt struct{}
 
 func (f *F32Gt) Opcode() opcode.Opcode {
@@ -56,6 +81,12 @@ func (f *F32Gt) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32Gt) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32Compare(r, func(a, b float32) bool {
+		return a > b
+	})
+}
+
 type F64Gt struct{}
 
 func (f *F64Gt) Opcode() opcode.Opcode {
@@ -65,3 +96,9 @@ func (f *F64Gt) Opcode() opcode.Opcode {
 func (f *F64Gt) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64Gt) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64Compare(r, func(a, b float64) bool {
+		return a > b
+	})
+}
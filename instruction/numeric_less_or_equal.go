@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32LeS struct{}
@@ -16,6 +17,12 @@ func (i *I32LeS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32LeS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return boolValue(a <= b)
+	})
+}
+
 type I32LeU struct{}
 
 func (i *I32LeU) Opcode() opcode.Opcode {
@@ -26,6 +33,12 @@ func (i *I32LeU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32LeU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return boolValue(uint32(a) <= uint32(b))
+	})
+}
+
 type I64LeS struct{}
 
 func (i *I64LeS) Opcode() opcode.Opcode {
@@ -36,6 +49,12 @@ func (i *I64LeS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64LeS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return boolValue(a <= b)
+	})
+}
+
 type I64LeU struct{}
 
 func (i *I64LeU) Opcode() opcode.Opcode {
@@ -46,6 +65,12 @@ func (i *I64LeU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64LeU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return boolValue(uint64(a) <= uint64(b))
+	})
+}
+
 type F32Le struct{}
 
 func (f *F32Le) Opcode() opcode.Opcode {
@@ -56,6 +81,12 @@ func (f *F32Le) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32Le) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32Compare(r, func(a, b float32) bool {
+		return a <= b
+	})
+}
+
 type F64Le struct{}
 
 func (f *F64Le) Opcode() opcode.Opcode {
@@ -65,3 +96,9 @@ func (f *F64Le) Opcode() opcode.Opcode {
 func (f *F64Le) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64Le) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64Compare(r, func(a, b float64) bool {
+		return a <= b
+	})
+}
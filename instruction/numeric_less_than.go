@@ -58,6 +58,12 @@ func (i *I32LtU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32LtU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return boolValue(uint32(a) < uint32(b))
+	})
+}
+
 type I64LtS struct{}
 
 func (i *I64LtS) Opcode() opcode.Opcode {
@@ -68,6 +74,12 @@ func (i *I64LtS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64LtS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return boolValue(a < b)
+	})
+}
+
 type I64LtU struct{}
 
 func (i *I64LtU) Opcode() opcode.Opcode {
@@ -78,6 +90,12 @@ func (i *I64LtU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64LtU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return boolValue(uint64(a) < uint64(b))
+	})
+}
+
 type F32Lt struct{}
 
 func (f *F32Lt) Opcode() opcode.Opcode {
@@ -88,6 +106,12 @@ func (f *F32Lt) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32Lt) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32Compare(r, func(a, b float32) bool {
+		return a < b
+	})
+}
+
 type F64Lt struct{}
 
 func (f *F64Lt) Opcode() opcode.Opcode {
@@ -97,3 +121,9 @@ func (f *F64Lt) Opcode() opcode.Opcode {
 func (f *F64Lt) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64Lt) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64Compare(r, func(a, b float64) bool {
+		return a < b
+	})
+}
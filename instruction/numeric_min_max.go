@@ -0,0 +1,88 @@
+package instruction
+
+import (
+	"io"
+
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type F32Min struct{}
+
+func (i *F32Min) Opcode() opcode.Opcode {
+	return opcode.OpcodeF32Min
+}
+
+func (i *F32Min) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F32Min) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32BinaryOp(r, func(a, b float32) float32 {
+		return fmin(a, b)
+	})
+}
+
+type F32Max struct{}
+
+func (i *F32Max) Opcode() opcode.Opcode {
+	return opcode.OpcodeF32Max
+}
+
+func (i *F32Max) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F32Max) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32BinaryOp(r, func(a, b float32) float32 {
+		return fmax(a, b)
+	})
+}
+
+type F64Min struct{}
+
+func (i *F64Min) Opcode() opcode.Opcode {
+	return opcode.OpcodeF64Min
+}
+
+func (i *F64Min) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F64Min) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64BinaryOp(r, func(a, b float64) float64 {
+		return fmin(a, b)
+	})
+}
+
+type F64Max struct{}
+
+func (i *F64Max) Opcode() opcode.Opcode {
+	return opcode.OpcodeF64Max
+}
+
+func (i *F64Max) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F64Max) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64BinaryOp(r, func(a, b float64) float64 {
+		return fmax(a, b)
+	})
+}
+
+// fmin is the builtin min which also quiets NaN operands as the spec requires.
+func fmin[T float32 | float64](a, b T) T {
+	if a != a || b != b {
+		return a + b
+	}
+	return min(a, b)
+}
+
+// fmax is the builtin max which also quiets NaN operands as the spec requires.
+func fmax[T float32 | float64](a, b T) T {
+	if a != a || b != b {
+		return a + b
+	}
+	return max(a, b)
+}
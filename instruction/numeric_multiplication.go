@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32Mul struct{}
@@ -16,6 +17,12 @@ func (i *I32Mul) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32Mul) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return a * b
+	})
+}
+
 type I64Mul struct{}
 
 func (i *I64Mul) Opcode() opcode.Opcode {
@@ -26,6 +33,12 @@ func (i *I64Mul) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64Mul) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return a * b
+	})
+}
+
 type F32Mul struct{}
 
 func (f *F32Mul) Opcode() opcode.Opcode {
@@ -36,6 +49,12 @@ func (f *F32Mul) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32Mul) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32BinaryOp(r, func(a, b float32) float32 {
+		return a * b
+	})
+}
+
 type F64Mul struct{}
 
 func (f *F64Mul) Opcode() opcode.Opcode {
@@ -45,3 +64,9 @@ func (f *F64Mul) Opcode() opcode.Opcode {
 func (f *F64Mul) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64Mul) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64BinaryOp(r, func(a, b float64) float64 {
+		return a * b
+	})
+}
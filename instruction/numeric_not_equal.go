@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32Ne struct{}
@@ -16,6 +17,12 @@ func (i *I32Ne) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32Ne) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return boolValue(a != b)
+	})
+}
+
 type I64Ne struct{}
 
 func (i *I64Ne) Opcode() opcode.Opcode {
@@ -26,6 +33,12 @@ func (i *I64Ne) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64Ne) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return boolValue(a != b)
+	})
+}
+
 type F32Ne struct{}
 
 func (f *F32Ne) Opcode() opcode.Opcode {
@@ -36,6 +49,12 @@ func (f *F32Ne) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32Ne) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32Compare(r, func(a, b float32) bool {
+		return a != b
+	})
+}
+
 type F64Ne struct{}
 
 func (f *F64Ne) Opcode() opcode.Opcode {
@@ -45,3 +64,9 @@ func (f *F64Ne) Opcode() opcode.Opcode {
 func (f *F64Ne) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64Ne) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64Compare(r, func(a, b float64) bool {
+		return a != b
+	})
+}
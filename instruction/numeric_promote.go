@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type F64PromoteF32 struct{}
@@ -15,3 +16,9 @@ func (f *F64PromoteF32) Opcode() opcode.Opcode {
 func (f *F64PromoteF32) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64PromoteF32) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueF32) runtime.Value {
+		return runtime.NewValueF64(float64(a.Float32()))
+	})
+}
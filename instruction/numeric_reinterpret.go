@@ -1,9 +1,11 @@
 package instruction
 
 import (
+	"encoding/binary"
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32ReinterpretF32 struct{}
@@ -16,6 +18,12 @@ func (i *I32ReinterpretF32) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32ReinterpretF32) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueF32) runtime.Value {
+		return runtime.ValueI32(binary.LittleEndian.Uint32(a[:]))
+	})
+}
+
 type I64ReinterpretF64 struct{}
 
 func (i *I64ReinterpretF64) Opcode() opcode.Opcode {
@@ -26,6 +34,12 @@ func (i *I64ReinterpretF64) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64ReinterpretF64) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueF64) runtime.Value {
+		return runtime.ValueI64(binary.LittleEndian.Uint64(a[:]))
+	})
+}
+
 type F32ReinterpretI32 struct{}
 
 func (f *F32ReinterpretI32) Opcode() opcode.Opcode {
@@ -36,6 +50,12 @@ func (f *F32ReinterpretI32) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32ReinterpretI32) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI32) runtime.Value {
+		return runtime.ValueF32(binary.LittleEndian.AppendUint32(nil, uint32(a)))
+	})
+}
+
 type F64ReinterpretI64 struct{}
 
 func (f *F64ReinterpretI64) Opcode() opcode.Opcode {
@@ -45,3 +65,9 @@ func (f *F64ReinterpretI64) Opcode() opcode.Opcode {
 func (f *F64ReinterpretI64) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64ReinterpretI64) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.ValueF64(binary.LittleEndian.AppendUint64(nil, uint64(a)))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32RemS struct{}
@@ -16,6 +17,16 @@ func (i *I32RemS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32RemS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedBinaryOp(r, func(a, b runtime.ValueI32) (runtime.Value, error) {
+		if b == 0 {
+			return nil, runtime.ErrIntegerDivideByZero
+		}
+		// NOTE: math.MinInt32 % -1 is 0 in Go as in WebAssembly.
+		return a % b, nil
+	})
+}
+
 type I32RemU struct{}
 
 func (i *I32RemU) Opcode() opcode.Opcode {
@@ -26,6 +37,15 @@ func (i *I32RemU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32RemU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedBinaryOp(r, func(a, b runtime.ValueI32) (runtime.Value, error) {
+		if b == 0 {
+			return nil, runtime.ErrIntegerDivideByZero
+		}
+		return runtime.ValueI32(uint32(a) % uint32(b)), nil
+	})
+}
+
 type I64RemS struct{}
 
 func (i *I64RemS) Opcode() opcode.Opcode {
@@ -36,6 +56,16 @@ func (i *I64RemS) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64RemS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedBinaryOp(r, func(a, b runtime.ValueI64) (runtime.Value, error) {
+		if b == 0 {
+			return nil, runtime.ErrIntegerDivideByZero
+		}
+		// NOTE: math.MinInt64 % -1 is 0 in Go as in WebAssembly.
+		return a % b, nil
+	})
+}
+
 type I64RemU struct{}
 
 func (i *I64RemU) Opcode() opcode.Opcode {
@@ -45,3 +75,12 @@ func (i *I64RemU) Opcode() opcode.Opcode {
 func (i *I64RemU) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (i *I64RemU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedBinaryOp(r, func(a, b runtime.ValueI64) (runtime.Value, error) {
+		if b == 0 {
+			return nil, runtime.ErrIntegerDivideByZero
+		}
+		return runtime.ValueI64(uint64(a) % uint64(b)), nil
+	})
+}
@@ -0,0 +1,73 @@
+package instruction
+
+import (
+	"io"
+	"math/bits"
+
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type I32Rotl struct{}
+
+func (i *I32Rotl) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32Rotl
+}
+
+func (i *I32Rotl) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32Rotl) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return runtime.ValueI32(bits.RotateLeft32(uint32(a), int(uint32(b)%32)))
+	})
+}
+
+type I32Rotr struct{}
+
+func (i *I32Rotr) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32Rotr
+}
+
+func (i *I32Rotr) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32Rotr) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return runtime.ValueI32(bits.RotateLeft32(uint32(a), -int(uint32(b)%32)))
+	})
+}
+
+type I64Rotl struct{}
+
+func (i *I64Rotl) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Rotl
+}
+
+func (i *I64Rotl) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Rotl) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return runtime.ValueI64(bits.RotateLeft64(uint64(a), int(uint64(b)%64)))
+	})
+}
+
+type I64Rotr struct{}
+
+func (i *I64Rotr) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Rotr
+}
+
+func (i *I64Rotr) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Rotr) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return runtime.ValueI64(bits.RotateLeft64(uint64(a), -int(uint64(b)%64)))
+	})
+}
@@ -0,0 +1,137 @@
+package instruction
+
+import (
+	"io"
+	"math"
+
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type F32Ceil struct{}
+
+func (i *F32Ceil) Opcode() opcode.Opcode {
+	return opcode.OpcodeF32Ceil
+}
+
+func (i *F32Ceil) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F32Ceil) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32UnaryOp(r, func(a float32) float32 {
+		return float32(math.Ceil(float64(a)))
+	})
+}
+
+type F32Floor struct{}
+
+func (i *F32Floor) Opcode() opcode.Opcode {
+	return opcode.OpcodeF32Floor
+}
+
+func (i *F32Floor) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F32Floor) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32UnaryOp(r, func(a float32) float32 {
+		return float32(math.Floor(float64(a)))
+	})
+}
+
+type F32Trunc struct{}
+
+func (i *F32Trunc) Opcode() opcode.Opcode {
+	return opcode.OpcodeF32Trunc
+}
+
+func (i *F32Trunc) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F32Trunc) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32UnaryOp(r, func(a float32) float32 {
+		return float32(math.Trunc(float64(a)))
+	})
+}
+
+type F32Nearest struct{}
+
+func (i *F32Nearest) Opcode() opcode.Opcode {
+	return opcode.OpcodeF32Nearest
+}
+
+func (i *F32Nearest) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F32Nearest) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32UnaryOp(r, func(a float32) float32 {
+		return float32(math.RoundToEven(float64(a)))
+	})
+}
+
+type F64Ceil struct{}
+
+func (i *F64Ceil) Opcode() opcode.Opcode {
+	return opcode.OpcodeF64Ceil
+}
+
+func (i *F64Ceil) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F64Ceil) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64UnaryOp(r, func(a float64) float64 {
+		return math.Ceil(a)
+	})
+}
+
+type F64Floor struct{}
+
+func (i *F64Floor) Opcode() opcode.Opcode {
+	return opcode.OpcodeF64Floor
+}
+
+func (i *F64Floor) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F64Floor) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64UnaryOp(r, func(a float64) float64 {
+		return math.Floor(a)
+	})
+}
+
+type F64Trunc struct{}
+
+func (i *F64Trunc) Opcode() opcode.Opcode {
+	return opcode.OpcodeF64Trunc
+}
+
+func (i *F64Trunc) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F64Trunc) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64UnaryOp(r, func(a float64) float64 {
+		return math.Trunc(a)
+	})
+}
+
+type F64Nearest struct{}
+
+func (i *F64Nearest) Opcode() opcode.Opcode {
+	return opcode.OpcodeF64Nearest
+}
+
+func (i *F64Nearest) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F64Nearest) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64UnaryOp(r, func(a float64) float64 {
+		return math.RoundToEven(a)
+	})
+}
@@ -0,0 +1,104 @@
+package instruction
+
+import (
+	"io"
+
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type I32Shl struct{}
+
+func (i *I32Shl) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32Shl
+}
+
+func (i *I32Shl) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32Shl) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return a << (uint32(b) % 32)
+	})
+}
+
+type I32ShrS struct{}
+
+func (i *I32ShrS) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32ShrS
+}
+
+func (i *I32ShrS) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32ShrS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return a >> (uint32(b) % 32)
+	})
+}
+
+type I32ShrU struct{}
+
+func (i *I32ShrU) Opcode() opcode.Opcode {
+	return opcode.OpcodeI32ShrU
+}
+
+func (i *I32ShrU) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I32ShrU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI32) runtime.Value {
+		return runtime.ValueI32(uint32(a) >> (uint32(b) % 32))
+	})
+}
+
+type I64Shl struct{}
+
+func (i *I64Shl) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64Shl
+}
+
+func (i *I64Shl) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64Shl) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return a << (uint64(b) % 64)
+	})
+}
+
+type I64ShrS struct{}
+
+func (i *I64ShrS) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64ShrS
+}
+
+func (i *I64ShrS) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64ShrS) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return a >> (uint64(b) % 64)
+	})
+}
+
+type I64ShrU struct{}
+
+func (i *I64ShrU) Opcode() opcode.Opcode {
+	return opcode.OpcodeI64ShrU
+}
+
+func (i *I64ShrU) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *I64ShrU) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return runtime.ValueI64(uint64(a) >> (uint64(b) % 64))
+	})
+}
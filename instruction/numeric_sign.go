@@ -0,0 +1,112 @@
+package instruction
+
+import (
+	"io"
+	"math"
+
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+// abs, neg and copysign only touch the sign bit, so they are done on the bit
+// pattern to leave NaN payloads intact.
+const (
+	f32SignBit = 1 << 31
+	f64SignBit = 1 << 63
+)
+
+type F32Abs struct{}
+
+func (i *F32Abs) Opcode() opcode.Opcode {
+	return opcode.OpcodeF32Abs
+}
+
+func (i *F32Abs) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F32Abs) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32UnaryOp(r, func(a float32) float32 {
+		return math.Float32frombits(math.Float32bits(a) &^ f32SignBit)
+	})
+}
+
+type F32Neg struct{}
+
+func (i *F32Neg) Opcode() opcode.Opcode {
+	return opcode.OpcodeF32Neg
+}
+
+func (i *F32Neg) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F32Neg) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32UnaryOp(r, func(a float32) float32 {
+		return math.Float32frombits(math.Float32bits(a) ^ f32SignBit)
+	})
+}
+
+type F32Copysign struct{}
+
+func (i *F32Copysign) Opcode() opcode.Opcode {
+	return opcode.OpcodeF32Copysign
+}
+
+func (i *F32Copysign) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F32Copysign) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32BinaryOp(r, func(a, b float32) float32 {
+		return math.Float32frombits(math.Float32bits(a)&^f32SignBit | math.Float32bits(b)&f32SignBit)
+	})
+}
+
+type F64Abs struct{}
+
+func (i *F64Abs) Opcode() opcode.Opcode {
+	return opcode.OpcodeF64Abs
+}
+
+func (i *F64Abs) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F64Abs) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64UnaryOp(r, func(a float64) float64 {
+		return math.Float64frombits(math.Float64bits(a) &^ f64SignBit)
+	})
+}
+
+type F64Neg struct{}
+
+func (i *F64Neg) Opcode() opcode.Opcode {
+	return opcode.OpcodeF64Neg
+}
+
+func (i *F64Neg) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F64Neg) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64UnaryOp(r, func(a float64) float64 {
+		return math.Float64frombits(math.Float64bits(a) ^ f64SignBit)
+	})
+}
+
+type F64Copysign struct{}
+
+func (i *F64Copysign) Opcode() opcode.Opcode {
+	return opcode.OpcodeF64Copysign
+}
+
+func (i *F64Copysign) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F64Copysign) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64BinaryOp(r, func(a, b float64) float64 {
+		return math.Float64frombits(math.Float64bits(a)&^f64SignBit | math.Float64bits(b)&f64SignBit)
+	})
+}
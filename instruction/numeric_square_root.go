@@ -0,0 +1,42 @@
+package instruction
+
+import (
+	"io"
+	"math"
+
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type F32Sqrt struct{}
+
+func (i *F32Sqrt) Opcode() opcode.Opcode {
+	return opcode.OpcodeF32Sqrt
+}
+
+func (i *F32Sqrt) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F32Sqrt) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32UnaryOp(r, func(a float32) float32 {
+		// NOTE: rounding the float64 square root to float32 is exact.
+		return float32(math.Sqrt(float64(a)))
+	})
+}
+
+type F64Sqrt struct{}
+
+func (i *F64Sqrt) Opcode() opcode.Opcode {
+	return opcode.OpcodeF64Sqrt
+}
+
+func (i *F64Sqrt) ReadOperandsFrom(r io.Reader) error {
+	return nil
+}
+
+func (i *F64Sqrt) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64UnaryOp(r, func(a float64) float64 {
+		return math.Sqrt(a)
+	})
+}
@@ -54,6 +54,12 @@ func (i *I64Sub) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64Sub) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return binaryOp(r, func(a, b runtime.ValueI64) runtime.Value {
+		return a - b
+	})
+}
+
 type F32Sub struct{}
 
 func (f *F32Sub) Opcode() opcode.Opcode {
@@ -64,6 +70,12 @@ func (f *F32Sub) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (*F32Sub) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f32BinaryOp(r, func(a, b float32) float32 {
+		return a - b
+	})
+}
+
 type F64Sub struct{}
 
 func (f *F64Sub) Opcode() opcode.Opcode {
@@ -73,3 +85,9 @@ func (f *F64Sub) Opcode() opcode.Opcode {
 func (f *F64Sub) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (*F64Sub) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return f64BinaryOp(r, func(a, b float64) float64 {
+		return a - b
+	})
+}
@@ -2,8 +2,10 @@ package instruction
 
 import (
 	"io"
+	"math"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32TruncF32S struct{}
@@ -16,6 +18,16 @@ func (i *I32TruncF32S) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32TruncF32S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedUnaryOp(r, func(a runtime.ValueF32) (runtime.Value, error) {
+		v, err := truncate(float64(a.Float32()), math.MinInt32, 1<<31)
+		if err != nil {
+			return nil, err
+		}
+		return runtime.ValueI32(int32(v)), nil
+	})
+}
+
 type I32TruncF32U struct{}
 
 func (i *I32TruncF32U) Opcode() opcode.Opcode {
@@ -26,6 +38,16 @@ func (i *I32TruncF32U) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32TruncF32U) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedUnaryOp(r, func(a runtime.ValueF32) (runtime.Value, error) {
+		v, err := truncate(float64(a.Float32()), 0, 1<<32)
+		if err != nil {
+			return nil, err
+		}
+		return runtime.ValueI32(uint32(v)), nil
+	})
+}
+
 type I32TruncF64S struct{}
 
 func (i *I32TruncF64S) Opcode() opcode.Opcode {
@@ -36,6 +58,16 @@ func (i *I32TruncF64S) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32TruncF64S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedUnaryOp(r, func(a runtime.ValueF64) (runtime.Value, error) {
+		v, err := truncate(a.Float64(), math.MinInt32, 1<<31)
+		if err != nil {
+			return nil, err
+		}
+		return runtime.ValueI32(int32(v)), nil
+	})
+}
+
 type I32TruncF64U struct{}
 
 func (i *I32TruncF64U) Opcode() opcode.Opcode {
@@ -46,6 +78,16 @@ func (i *I32TruncF64U) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I32TruncF64U) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedUnaryOp(r, func(a runtime.ValueF64) (runtime.Value, error) {
+		v, err := truncate(a.Float64(), 0, 1<<32)
+		if err != nil {
+			return nil, err
+		}
+		return runtime.ValueI32(uint32(v)), nil
+	})
+}
+
 type I64TruncF32S struct{}
 
 func (i *I64TruncF32S) Opcode() opcode.Opcode {
@@ -56,6 +98,16 @@ func (i *I64TruncF32S) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64TruncF32S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedUnaryOp(r, func(a runtime.ValueF32) (runtime.Value, error) {
+		v, err := truncate(float64(a.Float32()), math.MinInt64, 1<<63)
+		if err != nil {
+			return nil, err
+		}
+		return runtime.ValueI64(int64(v)), nil
+	})
+}
+
 type I64TruncF32U struct{}
 
 func (i *I64TruncF32U) Opcode() opcode.Opcode {
@@ -66,6 +118,16 @@ func (i *I64TruncF32U) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64TruncF32U) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedUnaryOp(r, func(a runtime.ValueF32) (runtime.Value, error) {
+		v, err := truncate(float64(a.Float32()), 0, 1<<64)
+		if err != nil {
+			return nil, err
+		}
+		return runtime.ValueI64(uint64(v)), nil
+	})
+}
+
 type I64TruncF64S struct{}
 
 func (i *I64TruncF64S) Opcode() opcode.Opcode {
@@ -76,6 +138,16 @@ func (i *I64TruncF64S) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
 
+func (i *I64TruncF64S) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedUnaryOp(r, func(a runtime.ValueF64) (runtime.Value, error) {
+		v, err := truncate(a.Float64(), math.MinInt64, 1<<63)
+		if err != nil {
+			return nil, err
+		}
+		return runtime.ValueI64(int64(v)), nil
+	})
+}
+
 type I64TruncF64U struct{}
 
 func (i *I64TruncF64U) Opcode() opcode.Opcode {
@@ -85,3 +157,27 @@ func (i *I64TruncF64U) Opcode() opcode.Opcode {
 func (i *I64TruncF64U) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (i *I64TruncF64U) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return checkedUnaryOp(r, func(a runtime.ValueF64) (runtime.Value, error) {
+		v, err := truncate(a.Float64(), 0, 1<<64)
+		if err != nil {
+			return nil, err
+		}
+		return runtime.ValueI64(uint64(v)), nil
+	})
+}
+
+// truncate truncates v toward zero and checks that the result is in [lo, hi).
+func truncate(v, lo, hi float64) (float64, error) {
+	if math.IsNaN(v) {
+		return 0, runtime.ErrInvalidConversionToInteger
+	}
+
+	v = math.Trunc(v)
+	if v < lo || hi <= v {
+		return 0, runtime.ErrIntegerOverflow
+	}
+
+	return v, nil
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 type I32WrapI64 struct{}
@@ -15,3 +16,9 @@ func (i *I32WrapI64) Opcode() opcode.Opcode {
 func (i *I32WrapI64) ReadOperandsFrom(r io.Reader) error {
 	return nil
 }
+
+func (i *I32WrapI64) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(a runtime.ValueI64) runtime.Value {
+		return runtime.ValueI32(int32(a))
+	})
+}
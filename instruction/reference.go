@@ -0,0 +1,71 @@
+package instruction
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Warashi/wasmium/leb128"
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/binary"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type RefNull struct {
+	Type binary.RefType
+}
+
+func (*RefNull) Opcode() opcode.Opcode { return opcode.OpcodeRefNull }
+
+func (i *RefNull) ReadOperandsFrom(r io.Reader) error {
+	var buf [1]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return fmt.Errorf("failed to read reference type: %w", err)
+	}
+	switch t := binary.RefType(buf[0]); t {
+	case binary.RefTypeFunc, binary.RefTypeExtern:
+		i.Type = t
+		return nil
+	default:
+		return fmt.Errorf("unsupported reference type: %2x", buf[0])
+	}
+}
+
+func (i *RefNull) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	r.PushStack(runtime.NullRef(i.Type))
+	return nil
+}
+
+type RefIsNull struct{}
+
+func (*RefIsNull) Opcode() opcode.Opcode { return opcode.OpcodeRefIsNull }
+
+func (*RefIsNull) ReadOperandsFrom(io.Reader) error { return nil }
+
+func (*RefIsNull) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	return unaryOp(r, func(v runtime.Ref) runtime.Value {
+		return boolValue(v.IsNull())
+	})
+}
+
+type RefFunc struct {
+	Index uint32
+}
+
+func (*RefFunc) Opcode() opcode.Opcode { return opcode.OpcodeRefFunc }
+
+func (i *RefFunc) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.Index, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *RefFunc) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	funcInst, err := r.Func(int(i.Index))
+	if err != nil {
+		return fmt.Errorf("failed to get function: %w", err)
+	}
+
+	r.PushStack(runtime.ValueFuncRef{Func: funcInst})
+
+	return nil
+}
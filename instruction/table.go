@@ -0,0 +1,308 @@
+package instruction
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Warashi/wasmium/leb128"
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type TableGet struct {
+	TableIndex uint32
+}
+
+func (*TableGet) Opcode() opcode.Opcode { return opcode.OpcodeTableGet }
+
+func (i *TableGet) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.TableIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *TableGet) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	index, err := popValue[runtime.ValueI32](r)
+	if err != nil {
+		return err
+	}
+
+	table, err := r.Table(int(i.TableIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get table: %w", err)
+	}
+
+	if len(table.Elements) <= int(uint32(index)) {
+		return runtime.ErrTableOutOfBounds
+	}
+
+	r.PushStack(table.Elements[uint32(index)])
+
+	return nil
+}
+
+type TableSet struct {
+	TableIndex uint32
+}
+
+func (*TableSet) Opcode() opcode.Opcode { return opcode.OpcodeTableSet }
+
+func (i *TableSet) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.TableIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *TableSet) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	ref, err := popValue[runtime.Ref](r)
+	if err != nil {
+		return err
+	}
+
+	index, err := popValue[runtime.ValueI32](r)
+	if err != nil {
+		return err
+	}
+
+	table, err := r.Table(int(i.TableIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get table: %w", err)
+	}
+
+	if len(table.Elements) <= int(uint32(index)) {
+		return runtime.ErrTableOutOfBounds
+	}
+
+	if err := checkRefType(table, ref); err != nil {
+		return err
+	}
+
+	table.Elements[uint32(index)] = ref
+
+	return nil
+}
+
+type FCTableInit struct {
+	ElementIndex uint32
+	TableIndex   uint32
+}
+
+func (*FCTableInit) Opcode() opcode.OpcodeFC { return opcode.OpcodeFCTableInit }
+
+func (i *FCTableInit) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.ElementIndex, err = leb128.Uint32(r)
+	if err != nil {
+		return err
+	}
+	i.TableIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *FCTableInit) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	d, s, n, err := popBulkOperands(r)
+	if err != nil {
+		return err
+	}
+
+	table, err := r.Table(int(i.TableIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get table: %w", err)
+	}
+
+	elem, err := r.Element(int(i.ElementIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get element: %w", err)
+	}
+
+	if !inBounds(s, n, len(elem.Elements)) || !inBounds(d, n, len(table.Elements)) {
+		return runtime.ErrTableOutOfBounds
+	}
+
+	copy(table.Elements[d:d+n], elem.Elements[s:s+n])
+
+	return nil
+}
+
+type FCElemDrop struct {
+	ElementIndex uint32
+}
+
+func (*FCElemDrop) Opcode() opcode.OpcodeFC { return opcode.OpcodeFCElemDrop }
+
+func (i *FCElemDrop) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.ElementIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *FCElemDrop) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	elem, err := r.Element(int(i.ElementIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get element: %w", err)
+	}
+
+	elem.Elements = nil
+
+	return nil
+}
+
+type FCTableCopy struct {
+	DstTableIndex uint32
+	SrcTableIndex uint32
+}
+
+func (*FCTableCopy) Opcode() opcode.OpcodeFC { return opcode.OpcodeFCTableCopy }
+
+func (i *FCTableCopy) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.DstTableIndex, err = leb128.Uint32(r)
+	if err != nil {
+		return err
+	}
+	i.SrcTableIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *FCTableCopy) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	d, s, n, err := popBulkOperands(r)
+	if err != nil {
+		return err
+	}
+
+	dst, err := r.Table(int(i.DstTableIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get table: %w", err)
+	}
+
+	src, err := r.Table(int(i.SrcTableIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get table: %w", err)
+	}
+
+	if !inBounds(s, n, len(src.Elements)) || !inBounds(d, n, len(dst.Elements)) {
+		return runtime.ErrTableOutOfBounds
+	}
+
+	copy(dst.Elements[d:d+n], src.Elements[s:s+n])
+
+	return nil
+}
+
+type FCTableGrow struct {
+	TableIndex uint32
+}
+
+func (*FCTableGrow) Opcode() opcode.OpcodeFC { return opcode.OpcodeFCTableGrow }
+
+func (i *FCTableGrow) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.TableIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *FCTableGrow) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	delta, err := popValue[runtime.ValueI32](r)
+	if err != nil {
+		return err
+	}
+
+	init, err := popValue[runtime.Ref](r)
+	if err != nil {
+		return err
+	}
+
+	table, err := r.Table(int(i.TableIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get table: %w", err)
+	}
+
+	if err := checkRefType(table, init); err != nil {
+		return err
+	}
+
+	r.PushStack(runtime.ValueI32(table.Grow(uint32(delta), init)))
+
+	return nil
+}
+
+type FCTableSize struct {
+	TableIndex uint32
+}
+
+func (*FCTableSize) Opcode() opcode.OpcodeFC { return opcode.OpcodeFCTableSize }
+
+func (i *FCTableSize) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.TableIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *FCTableSize) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	table, err := r.Table(int(i.TableIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get table: %w", err)
+	}
+
+	r.PushStack(runtime.ValueI32(table.Size()))
+
+	return nil
+}
+
+type FCTableFill struct {
+	TableIndex uint32
+}
+
+func (*FCTableFill) Opcode() opcode.OpcodeFC { return opcode.OpcodeFCTableFill }
+
+func (i *FCTableFill) ReadOperandsFrom(r io.Reader) error {
+	var err error
+	i.TableIndex, err = leb128.Uint32(r)
+	return err
+}
+
+func (i *FCTableFill) Execute(r runtime.Runtime, f *runtime.Frame) error {
+	n, err := popValue[runtime.ValueI32](r)
+	if err != nil {
+		return err
+	}
+
+	ref, err := popValue[runtime.Ref](r)
+	if err != nil {
+		return err
+	}
+
+	d, err := popValue[runtime.ValueI32](r)
+	if err != nil {
+		return err
+	}
+
+	table, err := r.Table(int(i.TableIndex))
+	if err != nil {
+		return fmt.Errorf("failed to get table: %w", err)
+	}
+
+	if err := checkRefType(table, ref); err != nil {
+		return err
+	}
+
+	start, count := uint64(uint32(d)), uint64(uint32(n))
+	if !inBounds(start, count, len(table.Elements)) {
+		return runtime.ErrTableOutOfBounds
+	}
+
+	region := table.Elements[start : start+count]
+	for j := range region {
+		region[j] = ref
+	}
+
+	return nil
+}
+
+// checkRefType checks that ref can be stored in table.
+func checkRefType(table *runtime.TableInst, ref runtime.Ref) error {
+	if want := runtime.ValueType(table.Type); ref.Type() != want {
+		return fmt.Errorf("%w: expected %v, got %v", runtime.ErrTypeMismatch, want, ref.Type())
+	}
+	return nil
+}
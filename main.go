@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"log/slog"
 	"os/signal"
 
@@ -49,31 +50,23 @@ func _main() (exitCode int) {
 	}
 	defer f.Close()
 
-	r, err := runtime.New(f)
+	r, err := runtime.New(f, wasip1.NewWasiPreview1().Options()...)
 	if err != nil {
 		slog.Error("failed to create runtime", slog.Any("error", err))
 		return 1
 	}
 
-	wasip1.NewWasiPreview1().Register(r)
-
-	sigCh, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	done := make(chan int)
-	go func() {
-		defer close(done)
-		if _, err := r.Call("_start"); err != nil {
-			slog.Error("failed to call _start", slog.Any("error", err))
-			done <- 1
+	if _, err := r.CallContext(ctx, "_start"); err != nil {
+		if errors.Is(err, context.Canceled) {
+			slog.Info("interrupted")
+			return 1
 		}
-	}()
-
-	select {
-	case <-sigCh.Done():
-		slog.Info("interrupted")
+		slog.Error("failed to call _start", slog.Any("error", err))
 		return 1
-	case c := <-done:
-		return c
 	}
+
+	return 0
 }
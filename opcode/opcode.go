@@ -33,7 +33,7 @@ const (
 	_
 	OpcodeDrop
 	OpcodeSelect
-	_
+	OpcodeTypedSelect
 	_
 	_
 	_
@@ -43,8 +43,8 @@ const (
 	OpcodeLocalTee
 	OpcodeGlobalGet
 	OpcodeGlobalSet
-	_
-	_
+	OpcodeTableGet
+	OpcodeTableSet
 	_
 	OpcodeI32Load
 	OpcodeI64Load
@@ -208,11 +208,11 @@ const (
 	OpcodeF32ReinterpretI32
 	OpcodeF64ReinterpretI64
 
-	_
-	_
-	_
-	_
-	_
+	OpcodeI32Extend8S
+	OpcodeI32Extend16S
+	OpcodeI64Extend8S
+	OpcodeI64Extend16S
+	OpcodeI64Extend32S
 	_
 	_
 	_
@@ -225,9 +225,9 @@ const (
 	_
 	_
 
-	_
-	_
-	_
+	OpcodeRefNull
+	OpcodeRefIsNull
+	OpcodeRefFunc
 	_
 	_
 	_
@@ -23,11 +23,14 @@ func _() {
 	_ = x[OpcodeCallIndirect-17]
 	_ = x[OpcodeDrop-26]
 	_ = x[OpcodeSelect-27]
+	_ = x[OpcodeTypedSelect-28]
 	_ = x[OpcodeLocalGet-32]
 	_ = x[OpcodeLocalSet-33]
 	_ = x[OpcodeLocalTee-34]
 	_ = x[OpcodeGlobalGet-35]
 	_ = x[OpcodeGlobalSet-36]
+	_ = x[OpcodeTableGet-37]
+	_ = x[OpcodeTableSet-38]
 	_ = x[OpcodeI32Load-40]
 	_ = x[OpcodeI64Load-41]
 	_ = x[OpcodeF32Load-42]
@@ -180,6 +183,14 @@ func _() {
 	_ = x[OpcodeI64ReinterpretF64-189]
 	_ = x[OpcodeF32ReinterpretI32-190]
 	_ = x[OpcodeF64ReinterpretI64-191]
+	_ = x[OpcodeI32Extend8S-192]
+	_ = x[OpcodeI32Extend16S-193]
+	_ = x[OpcodeI64Extend8S-194]
+	_ = x[OpcodeI64Extend16S-195]
+	_ = x[OpcodeI64Extend32S-196]
+	_ = x[OpcodeRefNull-208]
+	_ = x[OpcodeRefIsNull-209]
+	_ = x[OpcodeRefFunc-210]
 	_ = x[OpcodeGCSRPrefix-251]
 	_ = x[OpcodeFCPrefix-252]
 	_ = x[OpcodeSIMDPrefix-253]
@@ -189,19 +200,21 @@ func _() {
 const (
 	_Opcode_name_0 = "OpcodeUnreachableOpcodeNopOpcodeBlockOpcodeLoopOpcodeIfOpcodeElse"
 	_Opcode_name_1 = "OpcodeEndOpcodeBrOpcodeBrIfOpcodeBrTableOpcodeReturnOpcodeCallOpcodeCallIndirect"
-	_Opcode_name_2 = "OpcodeDropOpcodeSelect"
-	_Opcode_name_3 = "OpcodeLocalGetOpcodeLocalSetOpcodeLocalTeeOpcodeGlobalGetOpcodeGlobalSet"
-	_Opcode_name_4 = "OpcodeI32LoadOpcodeI64LoadOpcodeF32LoadOpcodeF64LoadOpcodeI32Load8SOpcodeI32Load8UOpcodeI32Load16SOpcodeI32Load16UOpcodeI64Load8SOpcodeI64Load8UOpcodeI64Load16SOpcodeI64Load16UOpcodeI64Load32SOpcodeI64Load32UOpcodeI32StoreOpcodeI64StoreOpcodeF32StoreOpcodeF64StoreOpcodeI32Store8OpcodeI32Store16OpcodeI64Store8OpcodeI64Store16OpcodeI64Store32OpcodeMemorySizeOpcodeMemoryGrowOpcodeI32ConstOpcodeI64ConstOpcodeF32ConstOpcodeF64ConstOpcodeI32EqzOpcodeI32EqOpcodeI32NeOpcodeI32LtSOpcodeI32LtUOpcodeI32GtSOpcodeI32GtUOpcodeI32LeSOpcodeI32LeUOpcodeI32GeSOpcodeI32GeUOpcodeI64EqzOpcodeI64EqOpcodeI64NeOpcodeI64LtSOpcodeI64LtUOpcodeI64GtSOpcodeI64GtUOpcodeI64LeSOpcodeI64LeUOpcodeI64GeSOpcodeI64GeUOpcodeF32EqOpcodeF32NeOpcodeF32LtOpcodeF32GtOpcodeF32LeOpcodeF32GeOpcodeF64EqOpcodeF64NeOpcodeF64LtOpcodeF64GtOpcodeF64LeOpcodeF64GeOpcodeI32ClzOpcodeI32CtzOpcodeI32PopcntOpcodeI32AddOpcodeI32SubOpcodeI32MulOpcodeI32DivSOpcodeI32DivUOpcodeI32RemSOpcodeI32RemUOpcodeI32AndOpcodeI32OrOpcodeI32XorOpcodeI32ShlOpcodeI32ShrSOpcodeI32ShrUOpcodeI32RotlOpcodeI32RotrOpcodeI64ClzOpcodeI64CtzOpcodeI64PopcntOpcodeI64AddOpcodeI64SubOpcodeI64MulOpcodeI64DivSOpcodeI64DivUOpcodeI64RemSOpcodeI64RemUOpcodeI64AndOpcodeI64OrOpcodeI64XorOpcodeI64ShlOpcodeI64ShrSOpcodeI64ShrUOpcodeI64RotlOpcodeI64RotrOpcodeF32AbsOpcodeF32NegOpcodeF32CeilOpcodeF32FloorOpcodeF32TruncOpcodeF32NearestOpcodeF32SqrtOpcodeF32AddOpcodeF32SubOpcodeF32MulOpcodeF32DivOpcodeF32MinOpcodeF32MaxOpcodeF32CopysignOpcodeF64AbsOpcodeF64NegOpcodeF64CeilOpcodeF64FloorOpcodeF64TruncOpcodeF64NearestOpcodeF64SqrtOpcodeF64AddOpcodeF64SubOpcodeF64MulOpcodeF64DivOpcodeF64MinOpcodeF64MaxOpcodeF64CopysignOpcodeI32WrapI64OpcodeI32TruncF32SOpcodeI32TruncF32UOpcodeI32TruncF64SOpcodeI32TruncF64UOpcodeI64ExtendI32SOpcodeI64ExtendI32UOpcodeI64TruncF32SOpcodeI64TruncF32UOpcodeI64TruncF64SOpcodeI64TruncF64UOpcodeF32ConvertI32SOpcodeF32ConvertI32UOpcodeF32ConvertI64SOpcodeF32ConvertI64UOpcodeF32DemoteF64OpcodeF64ConvertI32SOpcodeF64ConvertI32UOpcodeF64ConvertI64SOpcodeF64ConvertI64UOpcodeF64PromoteF32OpcodeI32ReinterpretF32OpcodeI64ReinterpretF64OpcodeF32ReinterpretI32OpcodeF64ReinterpretI64"
-	_Opcode_name_5 = "OpcodeGCSRPrefixOpcodeFCPrefixOpcodeSIMDPrefixOpcodeThreadsPrefix"
+	_Opcode_name_2 = "OpcodeDropOpcodeSelectOpcodeTypedSelect"
+	_Opcode_name_3 = "OpcodeLocalGetOpcodeLocalSetOpcodeLocalTeeOpcodeGlobalGetOpcodeGlobalSetOpcodeTableGetOpcodeTableSet"
+	_Opcode_name_4 = "OpcodeI32LoadOpcodeI64LoadOpcodeF32LoadOpcodeF64LoadOpcodeI32Load8SOpcodeI32Load8UOpcodeI32Load16SOpcodeI32Load16UOpcodeI64Load8SOpcodeI64Load8UOpcodeI64Load16SOpcodeI64Load16UOpcodeI64Load32SOpcodeI64Load32UOpcodeI32StoreOpcodeI64StoreOpcodeF32StoreOpcodeF64StoreOpcodeI32Store8OpcodeI32Store16OpcodeI64Store8OpcodeI64Store16OpcodeI64Store32OpcodeMemorySizeOpcodeMemoryGrowOpcodeI32ConstOpcodeI64ConstOpcodeF32ConstOpcodeF64ConstOpcodeI32EqzOpcodeI32EqOpcodeI32NeOpcodeI32LtSOpcodeI32LtUOpcodeI32GtSOpcodeI32GtUOpcodeI32LeSOpcodeI32LeUOpcodeI32GeSOpcodeI32GeUOpcodeI64EqzOpcodeI64EqOpcodeI64NeOpcodeI64LtSOpcodeI64LtUOpcodeI64GtSOpcodeI64GtUOpcodeI64LeSOpcodeI64LeUOpcodeI64GeSOpcodeI64GeUOpcodeF32EqOpcodeF32NeOpcodeF32LtOpcodeF32GtOpcodeF32LeOpcodeF32GeOpcodeF64EqOpcodeF64NeOpcodeF64LtOpcodeF64GtOpcodeF64LeOpcodeF64GeOpcodeI32ClzOpcodeI32CtzOpcodeI32PopcntOpcodeI32AddOpcodeI32SubOpcodeI32MulOpcodeI32DivSOpcodeI32DivUOpcodeI32RemSOpcodeI32RemUOpcodeI32AndOpcodeI32OrOpcodeI32XorOpcodeI32ShlOpcodeI32ShrSOpcodeI32ShrUOpcodeI32RotlOpcodeI32RotrOpcodeI64ClzOpcodeI64CtzOpcodeI64PopcntOpcodeI64AddOpcodeI64SubOpcodeI64MulOpcodeI64DivSOpcodeI64DivUOpcodeI64RemSOpcodeI64RemUOpcodeI64AndOpcodeI64OrOpcodeI64XorOpcodeI64ShlOpcodeI64ShrSOpcodeI64ShrUOpcodeI64RotlOpcodeI64RotrOpcodeF32AbsOpcodeF32NegOpcodeF32CeilOpcodeF32FloorOpcodeF32TruncOpcodeF32NearestOpcodeF32SqrtOpcodeF32AddOpcodeF32SubOpcodeF32MulOpcodeF32DivOpcodeF32MinOpcodeF32MaxOpcodeF32CopysignOpcodeF64AbsOpcodeF64NegOpcodeF64CeilOpcodeF64FloorOpcodeF64TruncOpcodeF64NearestOpcodeF64SqrtOpcodeF64AddOpcodeF64SubOpcodeF64MulOpcodeF64DivOpcodeF64MinOpcodeF64MaxOpcodeF64CopysignOpcodeI32WrapI64OpcodeI32TruncF32SOpcodeI32TruncF32UOpcodeI32TruncF64SOpcodeI32TruncF64UOpcodeI64ExtendI32SOpcodeI64ExtendI32UOpcodeI64TruncF32SOpcodeI64TruncF32UOpcodeI64TruncF64SOpcodeI64TruncF64UOpcodeF32ConvertI32SOpcodeF32ConvertI32UOpcodeF32ConvertI64SOpcodeF32ConvertI64UOpcodeF32DemoteF64OpcodeF64ConvertI32SOpcodeF64ConvertI32UOpcodeF64ConvertI64SOpcodeF64ConvertI64UOpcodeF64PromoteF32OpcodeI32ReinterpretF32OpcodeI64ReinterpretF64OpcodeF32ReinterpretI32OpcodeF64ReinterpretI64OpcodeI32Extend8SOpcodeI32Extend16SOpcodeI64Extend8SOpcodeI64Extend16SOpcodeI64Extend32S"
+	_Opcode_name_5 = "OpcodeRefNullOpcodeRefIsNullOpcodeRefFunc"
+	_Opcode_name_6 = "OpcodeGCSRPrefixOpcodeFCPrefixOpcodeSIMDPrefixOpcodeThreadsPrefix"
 )
 
 var (
 	_Opcode_index_0 = [...]uint8{0, 17, 26, 37, 47, 55, 65}
 	_Opcode_index_1 = [...]uint8{0, 9, 17, 27, 40, 52, 62, 80}
-	_Opcode_index_2 = [...]uint8{0, 10, 22}
-	_Opcode_index_3 = [...]uint8{0, 14, 28, 42, 57, 72}
-	_Opcode_index_4 = [...]uint16{0, 13, 26, 39, 52, 67, 82, 98, 114, 129, 144, 160, 176, 192, 208, 222, 236, 250, 264, 279, 295, 310, 326, 342, 358, 374, 388, 402, 416, 430, 442, 453, 464, 476, 488, 500, 512, 524, 536, 548, 560, 572, 583, 594, 606, 618, 630, 642, 654, 666, 678, 690, 701, 712, 723, 734, 745, 756, 767, 778, 789, 800, 811, 822, 834, 846, 861, 873, 885, 897, 910, 923, 936, 949, 961, 972, 984, 996, 1009, 1022, 1035, 1048, 1060, 1072, 1087, 1099, 1111, 1123, 1136, 1149, 1162, 1175, 1187, 1198, 1210, 1222, 1235, 1248, 1261, 1274, 1286, 1298, 1311, 1325, 1339, 1355, 1368, 1380, 1392, 1404, 1416, 1428, 1440, 1457, 1469, 1481, 1494, 1508, 1522, 1538, 1551, 1563, 1575, 1587, 1599, 1611, 1623, 1640, 1656, 1674, 1692, 1710, 1728, 1747, 1766, 1784, 1802, 1820, 1838, 1858, 1878, 1898, 1918, 1936, 1956, 1976, 1996, 2016, 2035, 2058, 2081, 2104, 2127}
-	_Opcode_index_5 = [...]uint8{0, 16, 30, 46, 65}
+	_Opcode_index_2 = [...]uint8{0, 10, 22, 39}
+	_Opcode_index_3 = [...]uint8{0, 14, 28, 42, 57, 72, 86, 100}
+	_Opcode_index_4 = [...]uint16{0, 13, 26, 39, 52, 67, 82, 98, 114, 129, 144, 160, 176, 192, 208, 222, 236, 250, 264, 279, 295, 310, 326, 342, 358, 374, 388, 402, 416, 430, 442, 453, 464, 476, 488, 500, 512, 524, 536, 548, 560, 572, 583, 594, 606, 618, 630, 642, 654, 666, 678, 690, 701, 712, 723, 734, 745, 756, 767, 778, 789, 800, 811, 822, 834, 846, 861, 873, 885, 897, 910, 923, 936, 949, 961, 972, 984, 996, 1009, 1022, 1035, 1048, 1060, 1072, 1087, 1099, 1111, 1123, 1136, 1149, 1162, 1175, 1187, 1198, 1210, 1222, 1235, 1248, 1261, 1274, 1286, 1298, 1311, 1325, 1339, 1355, 1368, 1380, 1392, 1404, 1416, 1428, 1440, 1457, 1469, 1481, 1494, 1508, 1522, 1538, 1551, 1563, 1575, 1587, 1599, 1611, 1623, 1640, 1656, 1674, 1692, 1710, 1728, 1747, 1766, 1784, 1802, 1820, 1838, 1858, 1878, 1898, 1918, 1936, 1956, 1976, 1996, 2016, 2035, 2058, 2081, 2104, 2127, 2144, 2162, 2179, 2197, 2215}
+	_Opcode_index_5 = [...]uint8{0, 13, 28, 41}
+	_Opcode_index_6 = [...]uint8{0, 16, 30, 46, 65}
 )
 
 func (i Opcode) String() string {
@@ -211,18 +224,21 @@ func (i Opcode) String() string {
 	case 11 <= i && i <= 17:
 		i -= 11
 		return _Opcode_name_1[_Opcode_index_1[i]:_Opcode_index_1[i+1]]
-	case 26 <= i && i <= 27:
+	case 26 <= i && i <= 28:
 		i -= 26
 		return _Opcode_name_2[_Opcode_index_2[i]:_Opcode_index_2[i+1]]
-	case 32 <= i && i <= 36:
+	case 32 <= i && i <= 38:
 		i -= 32
 		return _Opcode_name_3[_Opcode_index_3[i]:_Opcode_index_3[i+1]]
-	case 40 <= i && i <= 191:
+	case 40 <= i && i <= 196:
 		i -= 40
 		return _Opcode_name_4[_Opcode_index_4[i]:_Opcode_index_4[i+1]]
+	case 208 <= i && i <= 210:
+		i -= 208
+		return _Opcode_name_5[_Opcode_index_5[i]:_Opcode_index_5[i+1]]
 	case 251 <= i && i <= 254:
 		i -= 251
-		return _Opcode_name_5[_Opcode_index_5[i]:_Opcode_index_5[i+1]]
+		return _Opcode_name_6[_Opcode_index_6[i]:_Opcode_index_6[i+1]]
 	default:
 		return "Opcode(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
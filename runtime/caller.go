@@ -0,0 +1,69 @@
+package runtime
+
+import (
+	"context"
+
+	tbinary "github.com/Warashi/wasmium/types/binary"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+// Caller is the instance that calls a host function.
+// It gives the host access to the entities exported by the caller, such as its "memory".
+type Caller struct {
+	ctx      context.Context
+	instance *Runtime
+}
+
+func newCaller(ctx context.Context, instance *Runtime) *Caller {
+	return &Caller{ctx: ctx, instance: instance}
+}
+
+// Context returns the context of the call.
+func (c *Caller) Context() context.Context {
+	return c.ctx
+}
+
+// Memory returns the memory exported by the caller as name.
+func (c *Caller) Memory(name string) (*runtime.MemoryInst, bool) {
+	export, ok := c.instance.store.module.Exported(name)
+	if !ok {
+		return nil, false
+	}
+	desc, ok := export.Desc.(tbinary.ExportDescMemory)
+	if !ok || len(c.instance.store.memories) <= int(desc.Index) {
+		return nil, false
+	}
+	return c.instance.store.memories[desc.Index], true
+}
+
+// Global returns the global exported by the caller as name.
+func (c *Caller) Global(name string) (*runtime.GlobalInst, bool) {
+	export, ok := c.instance.store.module.Exported(name)
+	if !ok {
+		return nil, false
+	}
+	desc, ok := export.Desc.(tbinary.ExportDescGlobal)
+	if !ok || len(c.instance.store.globals) <= int(desc.Index) {
+		return nil, false
+	}
+	return c.instance.store.globals[desc.Index], true
+}
+
+// Call calls the function exported by the caller as name.
+// It may be used while the call of the host function is in progress, for example to call an allocator of the module.
+func (c *Caller) Call(name string, args ...runtime.Value) ([]runtime.Value, error) {
+	return c.instance.CallContext(c.ctx, name, args...)
+}
+
+// Func returns the function exported by the caller as name.
+func (c *Caller) Func(name string) (runtime.FuncInst, bool) {
+	export, ok := c.instance.store.module.Exported(name)
+	if !ok {
+		return nil, false
+	}
+	desc, ok := export.Desc.(tbinary.ExportDescFunc)
+	if !ok || len(c.instance.store.funcs) <= int(desc.Index) {
+		return nil, false
+	}
+	return c.instance.store.funcs[desc.Index], true
+}
@@ -6,6 +6,6 @@ func (r *Runtime) Store() *Store {
 	return r.store
 }
 
-func (s *Store) Memories() []runtime.MemoryInst {
+func (s *Store) Memories() []*runtime.MemoryInst {
 	return s.memories
 }
@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Warashi/wasmium/instruction"
+	"github.com/Warashi/wasmium/opcode"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+// SetFuel sets the remaining fuel and enables fuel metering.
+func (r *Runtime) SetFuel(fuel uint64) {
+	r.metered = true
+	r.fuel = fuel
+}
+
+// AddFuel adds to the remaining fuel and enables fuel metering.
+func (r *Runtime) AddFuel(fuel uint64) {
+	r.metered = true
+	r.fuel += fuel
+}
+
+// Fuel returns the remaining fuel.
+func (r *Runtime) Fuel() uint64 {
+	return r.fuel
+}
+
+// Resume continues the call that ran out of fuel, typically after AddFuel.
+// It returns the results of the original call.
+func (r *Runtime) Resume() ([]runtime.Value, error) {
+	return r.ResumeContext(context.Background())
+}
+
+// ResumeContext is like Resume, but continues the call with ctx.
+func (r *Runtime) ResumeContext(ctx context.Context) (results []runtime.Value, err error) {
+	defer r.withContext(ctx)()
+
+	if r.suspended == nil {
+		return nil, fmt.Errorf("no suspended call")
+	}
+
+	a := *r.suspended
+	r.suspended = nil
+
+	// NOTE: the instruction that ran out of fuel has not been executed yet.
+	r.callStack[len(r.callStack)-1].ProgramCounter--
+
+	defer r.recoverTrap(a.depth, a.bottom, &err)
+
+	return r.complete(a, true)
+}
+
+func (r *Runtime) fuelCost(inst runtime.Instruction) uint64 {
+	if i, ok := inst.(*instruction.FCPrefix); ok {
+		if cost, ok := r.fcFuelCosts[i.FC.Opcode()]; ok {
+			return cost
+		}
+	}
+	if i, ok := inst.(interface{ Opcode() opcode.Opcode }); ok {
+		if cost, ok := r.fuelCosts[i.Opcode()]; ok {
+			return cost
+		}
+	}
+	return 1
+}
@@ -1,6 +1,36 @@
 package runtime
 
-import "github.com/Warashi/wasmium/types/runtime"
+import (
+	tbinary "github.com/Warashi/wasmium/types/binary"
+	"github.com/Warashi/wasmium/types/runtime"
+)
 
-type ImportFunc func(*Store, ...runtime.Value) ([]runtime.Value, error)
-type Import map[string]map[string]ImportFunc
+// ImportFunc is a host function. It receives the calling instance and the arguments of the call.
+type ImportFunc func(*Caller, ...runtime.Value) ([]runtime.Value, error)
+
+// HostFunc is a function provided by the host together with its signature.
+// The signature is checked against the import when a module is instantiated.
+type HostFunc struct {
+	FuncType tbinary.FuncType
+	Func     ImportFunc
+}
+
+type Import map[string]map[string]HostFunc
+
+func (i Import) add(module string, name string, fn HostFunc) {
+	if _, ok := i[module]; !ok {
+		i[module] = make(map[string]HostFunc)
+	}
+	i[module][name] = fn
+}
+
+func (i Import) lookup(module string, name string) (HostFunc, bool) {
+	fn, ok := i[module][name]
+	return fn, ok
+}
+
+// importName identifies an imported entity by its module and field name.
+type importName struct {
+	module string
+	name   string
+}
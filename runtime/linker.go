@@ -0,0 +1,81 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	tbinary "github.com/Warashi/wasmium/types/binary"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+// Linker resolves imports against the exports of instances registered under a module name.
+type Linker struct {
+	instances map[string]*Runtime
+}
+
+func NewLinker() *Linker {
+	return &Linker{
+		instances: make(map[string]*Runtime),
+	}
+}
+
+// Register makes the exports of r available to later instantiations as the module name.
+func (l *Linker) Register(name string, r *Runtime) {
+	l.instances[name] = r
+}
+
+// Instantiate creates an instance of c, resolving its imports against the registered instances.
+// Imports from modules that are not registered are left to opts,
+// and opts never override an import that is resolved against a registered instance.
+func (l *Linker) Instantiate(c *CompiledModule, opts ...Option) (*Runtime, error) {
+	var errs []error
+	linked := make([]Option, 0, len(c.module.ImportSection()))
+	for _, impt := range c.module.ImportSection() {
+		r, ok := l.instances[impt.Module]
+		if !ok {
+			continue
+		}
+		opt, err := r.link(impt)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s.%s", err, impt.Module, impt.Field))
+			continue
+		}
+		linked = append(linked, opt)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to link imports: %w", errors.Join(errs...))
+	}
+
+	// NOTE: the last option for an import wins, so the linked ones are applied after opts.
+	return c.Instantiate(slices.Concat(opts, linked)...)
+}
+
+// link returns an option that provides the export of r requested by impt.
+func (r *Runtime) link(impt tbinary.Import) (Option, error) {
+	export, ok := r.store.module.Exported(impt.Field)
+	if !ok {
+		return nil, runtime.ErrUnknownImport
+	}
+
+	switch desc := export.Desc.(type) {
+	case tbinary.ExportDescFunc:
+		if _, ok := impt.Desc.(tbinary.ImportDescFunc); ok && int(desc.Index) < len(r.store.funcs) {
+			return withFunc(impt.Module, impt.Field, r.store.funcs[desc.Index]), nil
+		}
+	case tbinary.ExportDescTable:
+		if _, ok := impt.Desc.(tbinary.ImportDescTable); ok && int(desc.Index) < len(r.store.tables) {
+			return WithTable(impt.Module, impt.Field, r.store.tables[desc.Index]), nil
+		}
+	case tbinary.ExportDescMemory:
+		if _, ok := impt.Desc.(tbinary.ImportDescMemory); ok && int(desc.Index) < len(r.store.memories) {
+			return WithMemory(impt.Module, impt.Field, r.store.memories[desc.Index]), nil
+		}
+	case tbinary.ExportDescGlobal:
+		if _, ok := impt.Desc.(tbinary.ImportDescGlobal); ok && int(desc.Index) < len(r.store.globals) {
+			return WithGlobal(impt.Module, impt.Field, r.store.globals[desc.Index]), nil
+		}
+	}
+
+	return nil, runtime.ErrIncompatibleImportType
+}
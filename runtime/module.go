@@ -0,0 +1,114 @@
+package runtime
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+
+	"github.com/Warashi/wasmium/binary"
+	tbinary "github.com/Warashi/wasmium/types/binary"
+	"github.com/Warashi/wasmium/types/instruction"
+	"github.com/Warashi/wasmium/types/runtime"
+	"github.com/Warashi/wasmium/validation"
+)
+
+// CompiledModule is a module decoded and converted for execution.
+// It is never modified after Compile, so it can be shared across goroutines
+// and instantiated many times.
+type CompiledModule struct {
+	module *binary.Module
+	// codes are the module-defined functions in the order of the function section.
+	codes []runtime.InternalFuncInst
+}
+
+// Compile decodes a module from r, validates it and converts its instructions.
+func Compile(r io.Reader) (*CompiledModule, error) {
+	module, err := binary.NewModule(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create module: %w", err)
+	}
+
+	return compile(module)
+}
+
+func compile(module *binary.Module) (*CompiledModule, error) {
+	if err := validation.Validate(module); err != nil {
+		return nil, fmt.Errorf("failed to validate module: %w", err)
+	}
+
+	// NOTE: module-defined functions follow the imported ones in the function index space.
+	var index uint32
+	for _, impt := range module.ImportSection() {
+		if _, ok := impt.Desc.(tbinary.ImportDescFunc); ok {
+			index++
+		}
+	}
+
+	codes := make([]runtime.InternalFuncInst, 0, len(module.CodeSection()))
+	for body, typeIdx := range zipSlice(module.CodeSection(), module.FunctionSection()) {
+		funcType := module.TypeSection()[typeIdx]
+
+		// NOTE: the validator limits the number of locals, so they can be expanded here.
+		localslen := 0
+
+		for _, local := range body.Locals {
+			localslen += int(local.TypeCount)
+		}
+		locals := make([]tbinary.ValueType, 0, localslen)
+
+		for _, local := range body.Locals {
+			for range local.TypeCount {
+				locals = append(locals, local.ValueType)
+			}
+		}
+
+		insts, err := instruction.Convert(body.Code, module.TypeSection())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert instructions: %w", err)
+		}
+
+		codes = append(codes, runtime.InternalFuncInst{
+			Index:    index,
+			FuncType: funcType,
+			Code: runtime.Func{
+				Locals: locals,
+				Body:   insts,
+			},
+		})
+		index++
+	}
+
+	return &CompiledModule{
+		module: module,
+		codes:  codes,
+	}, nil
+}
+
+// Instantiate creates an independent instance of the module.
+// Each instance has its own memories, tables, globals and stacks.
+func (c *CompiledModule) Instantiate(opts ...Option) (*Runtime, error) {
+	cfg := newConfig(opts...)
+	rt := &Runtime{
+		imports:      cfg.imports,
+		maxCallDepth: cmp.Or(cfg.maxCallDepth, DefaultMaxCallDepth),
+		maxStackSize: cmp.Or(cfg.maxStackSize, DefaultMaxStackSize),
+		metered:      cfg.metered,
+		fuel:         cfg.fuel,
+		fuelCosts:    cfg.fuelCosts,
+		fcFuelCosts:  cfg.fcFuelCosts,
+	}
+
+	store, err := c.newStore(rt, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	rt.store = store
+
+	if start, ok := c.module.Start(); ok {
+		if _, err := rt.callFunc(store.funcs[start]); err != nil {
+			return nil, fmt.Errorf("failed to run start function: %w", err)
+		}
+	}
+
+	return rt, nil
+}
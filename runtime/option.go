@@ -0,0 +1,154 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/Warashi/wasmium/opcode"
+	tbinary "github.com/Warashi/wasmium/types/binary"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+type Option func(*config)
+
+type config struct {
+	memoryLimitPages uint32
+	maxCallDepth     int
+	maxStackSize     int
+	metered          bool
+	fuel             uint64
+	fuelCosts        map[opcode.Opcode]uint64
+	fcFuelCosts      map[opcode.OpcodeFC]uint64
+	imports          Import
+	funcs            map[importName]runtime.FuncInst
+	memories         map[importName]*runtime.MemoryInst
+	tables           map[importName]*runtime.TableInst
+	globals          map[importName]*runtime.GlobalInst
+	errs             []error
+}
+
+func newConfig(opts ...Option) config {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithMemoryLimitPages limits every memory of the module to the given number of pages.
+// memory.grow fails beyond the limit even if the module allows a larger maximum.
+func WithMemoryLimitPages(pages uint32) Option {
+	return func(c *config) {
+		c.memoryLimitPages = pages
+	}
+}
+
+// WithMaxCallDepth limits the number of nested calls of the instance to depth.
+// A call beyond the limit traps with runtime.ErrStackExhausted. The default is DefaultMaxCallDepth.
+func WithMaxCallDepth(depth int) Option {
+	return func(c *config) {
+		c.maxCallDepth = depth
+	}
+}
+
+// WithMaxStackSize limits the number of values on the stack of the instance to size.
+// The limit is checked when a function is entered, and a call beyond it traps with runtime.ErrStackExhausted.
+// The default is DefaultMaxStackSize.
+func WithMaxStackSize(size int) Option {
+	return func(c *config) {
+		c.maxStackSize = size
+	}
+}
+
+// WithFuel enables fuel metering with the given amount of fuel.
+// Every executed instruction consumes fuel, and execution traps with runtime.ErrOutOfFuel when it runs out.
+func WithFuel(fuel uint64) Option {
+	return func(c *config) {
+		c.metered = true
+		c.fuel = fuel
+	}
+}
+
+// WithFuelCosts sets the fuel consumed by instructions of each opcode. Other instructions consume 1.
+// Instructions with the 0xFC prefix are charged as opcode.OpcodeFCPrefix unless WithFCFuelCosts sets their cost.
+func WithFuelCosts(costs map[opcode.Opcode]uint64) Option {
+	return func(c *config) {
+		c.fuelCosts = costs
+	}
+}
+
+// WithFCFuelCosts sets the fuel consumed by instructions with the 0xFC prefix, by their sub-opcode.
+// It takes precedence over the cost of opcode.OpcodeFCPrefix set by WithFuelCosts.
+func WithFCFuelCosts(costs map[opcode.OpcodeFC]uint64) Option {
+	return func(c *config) {
+		c.fcFuelCosts = costs
+	}
+}
+
+// WithImport provides a host function of the given type for a function import.
+// Instantiation fails if the type does not match the import.
+func WithImport(module string, name string, funcType tbinary.FuncType, fn ImportFunc) Option {
+	return func(c *config) {
+		if c.imports == nil {
+			c.imports = make(Import)
+		}
+		c.imports.add(module, name, HostFunc{FuncType: funcType, Func: fn})
+	}
+}
+
+// WithHostFunc provides a Go function for a function import, inferring its type as NewHostFunc does.
+// An unsupported function makes instantiation fail.
+func WithHostFunc(module string, name string, fn any) Option {
+	return func(c *config) {
+		host, err := NewHostFunc(fn)
+		if err != nil {
+			c.errs = append(c.errs, fmt.Errorf("invalid host function %s.%s: %w", module, name, err))
+			return
+		}
+		if c.imports == nil {
+			c.imports = make(Import)
+		}
+		c.imports.add(module, name, host)
+	}
+}
+
+// WithMemory provides a host memory for a memory import.
+// The memory is shared, so the host sees every write made by the module.
+func WithMemory(module string, name string, memory *runtime.MemoryInst) Option {
+	return func(c *config) {
+		if c.memories == nil {
+			c.memories = make(map[importName]*runtime.MemoryInst)
+		}
+		c.memories[importName{module, name}] = memory
+	}
+}
+
+// WithTable provides a host table for a table import.
+func WithTable(module string, name string, table *runtime.TableInst) Option {
+	return func(c *config) {
+		if c.tables == nil {
+			c.tables = make(map[importName]*runtime.TableInst)
+		}
+		c.tables[importName{module, name}] = table
+	}
+}
+
+// WithGlobal provides a host global for a global import.
+// A mutable global is shared, so updates by either side are visible to the other.
+func WithGlobal(module string, name string, global *runtime.GlobalInst) Option {
+	return func(c *config) {
+		if c.globals == nil {
+			c.globals = make(map[importName]*runtime.GlobalInst)
+		}
+		c.globals[importName{module, name}] = global
+	}
+}
+
+// withFunc provides a function of another instance for a function import.
+func withFunc(module string, name string, f runtime.FuncInst) Option {
+	return func(c *config) {
+		if c.funcs == nil {
+			c.funcs = make(map[importName]runtime.FuncInst)
+		}
+		c.funcs[importName{module, name}] = f
+	}
+}
@@ -1,39 +1,76 @@
 package runtime
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 
-	bin "github.com/Warashi/wasmium/binary"
+	"github.com/Warashi/wasmium/opcode"
 	"github.com/Warashi/wasmium/stack"
 	"github.com/Warashi/wasmium/types/binary"
 	"github.com/Warashi/wasmium/types/runtime"
 )
 
+const (
+	DefaultMaxCallDepth = 10000
+	DefaultMaxStackSize = 1 << 20
+)
+
 type Runtime struct {
 	store     *Store
 	stack     stack.Stack[runtime.Value]
 	callStack stack.Stack[*runtime.Frame]
 	imports   Import
+
+	maxCallDepth int
+	maxStackSize int
+	// ctx is the context of the call in progress.
+	ctx context.Context
+
+	metered   bool
+	fuel      uint64
+	fuelCosts map[opcode.Opcode]uint64
+	// fcFuelCosts are the costs of instructions with the 0xFC prefix by their sub-opcode.
+	fcFuelCosts map[opcode.OpcodeFC]uint64
+	// suspended is the call that ran out of fuel and can be resumed.
+	suspended *activation
 }
 
-func New(r io.Reader) (*Runtime, error) {
-	module, err := bin.NewModule(r)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create module: %w", err)
-	}
+// activation is a call of an internal function executed by execute.
+type activation struct {
+	// depth is the length of the call stack and bottom is the height of the value stack before the call.
+	depth, bottom int
+	arity         int
+}
 
-	store, err := NewStore(module)
+// New compiles a module from r and instantiates it.
+func New(r io.Reader, opts ...Option) (*Runtime, error) {
+	c, err := Compile(r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create store: %w", err)
+		return nil, err
 	}
 
-	return &Runtime{
-		store: store,
-	}, nil
+	return c.Instantiate(opts...)
 }
 
 func (r *Runtime) Call(name string, args ...runtime.Value) ([]runtime.Value, error) {
+	return r.CallContext(context.Background(), name, args...)
+}
+
+// CallContext calls the exported function name with ctx.
+// When ctx is done, the execution stops at the next loop iteration or call,
+// and an error wrapping runtime.ErrInterrupted and the cause of ctx is returned.
+// Host functions receive ctx through Caller.
+func (r *Runtime) CallContext(ctx context.Context, name string, args ...runtime.Value) ([]runtime.Value, error) {
+	defer r.withContext(ctx)()
+
+	// NOTE: a new call abandons the call suspended by running out of fuel.
+	if r.suspended != nil {
+		r.unwind(r.suspended.depth, r.suspended.bottom)
+		r.suspended = nil
+	}
+
 	export, ok := r.store.module.Exported(name)
 	if !ok {
 		return nil, fmt.Errorf("export not found: %s", name)
@@ -45,33 +82,64 @@ func (r *Runtime) Call(name string, args ...runtime.Value) ([]runtime.Value, err
 			return nil, fmt.Errorf("invalid function index: %d", desc.Index)
 		}
 
-		f := r.store.funcs[desc.Index]
+		return r.callFunc(r.store.funcs[desc.Index], args...)
+	}
 
-		for _, arg := range args {
-			r.stack.Push(arg)
-		}
+	return nil, fmt.Errorf("unexpected export description: %T", export.Desc)
+}
 
-		switch f := f.(type) {
-		case runtime.InternalFuncInst:
-			return r.InvokeInternal(f)
-		case runtime.ExternalFuncInst:
-			return r.InvokeExternal(f)
-		default:
-			return nil, fmt.Errorf("unexpected function instance: %T", f)
-		}
+// callFunc invokes f on behalf of the host.
+func (r *Runtime) callFunc(f runtime.FuncInst, args ...runtime.Value) (results []runtime.Value, err error) {
+	defer r.recoverTrap(r.callStack.Len(), r.stack.Len(), &err)
+
+	return r.invoke(f, args...)
+}
+
+// recoverTrap converts a panic during a call into a trap and unwinds the call.
+// It is the last resort, as the interpreter returns errors for every known failure.
+func (r *Runtime) recoverTrap(depth, bottom int, err *error) {
+	v := recover()
+	if v == nil {
+		return
 	}
 
-	return nil, fmt.Errorf("unexpected export description: %T", export.Desc)
+	trap := r.trap(fmt.Errorf("%w: %v", runtime.ErrInternal, v), depth)
+	r.unwind(depth, bottom)
+	*err = trap
+}
+
+// withContext sets the context of the call in progress and returns a function restoring the previous one.
+func (r *Runtime) withContext(ctx context.Context) func() {
+	prev := r.ctx
+	r.ctx = ctx
+	return func() { r.ctx = prev }
 }
 
-func (r *Runtime) AddImport(module string, name string, fn ImportFunc) {
-	if r.imports == nil {
-		r.imports = make(Import)
+func (r *Runtime) context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
 	}
-	if _, ok := r.imports[module]; !ok {
-		r.imports[module] = make(map[string]ImportFunc)
+	return r.ctx
+}
+
+func (r *Runtime) invoke(f runtime.FuncInst, args ...runtime.Value) ([]runtime.Value, error) {
+	for _, arg := range args {
+		r.stack.Push(arg)
+	}
+
+	switch f := f.(type) {
+	case runtime.InternalFuncInst:
+		// NOTE: only a call not nested in another execution of this instance can be resumed,
+		// as resuming a nested one would skip the Go frames of the calls in between.
+		if (f.Instance == nil || f.Instance == runtime.Runtime(r)) && r.callStack.Len() == 0 {
+			return r.run(f, true)
+		}
+		return r.InvokeInternal(f)
+	case runtime.ExternalFuncInst:
+		return r.InvokeExternal(f)
+	default:
+		return nil, fmt.Errorf("unexpected function instance: %T", f)
 	}
-	r.imports[module][name] = fn
 }
 
 func (r *Runtime) GlobalGet(index int) (runtime.Value, error) {
@@ -92,6 +160,19 @@ func (r *Runtime) GlobalSet(index int, value runtime.Value) error {
 	return nil
 }
 
+// Memory implements types.Runtime.
+func (r *Runtime) Memory(n int) (*runtime.MemoryInst, error) {
+	return r.store.Memory(n)
+}
+
+// Data implements types.Runtime.
+func (r *Runtime) Data(i int) (*runtime.DataInst, error) {
+	if i < 0 || len(r.store.datas) <= i {
+		return nil, fmt.Errorf("invalid data index: %d", i)
+	}
+	return r.store.datas[i], nil
+}
+
 func (r *Runtime) WriteMemoryAt(n int, data []byte, offset int64) (int, error) {
 	if n < 0 || len(r.store.memories) <= n {
 		return 0, fmt.Errorf("invalid memory index: %d", n)
@@ -116,22 +197,48 @@ func (r *Runtime) Func(i int) (runtime.FuncInst, error) {
 	return r.store.funcs[i], nil
 }
 
+// FuncType implements types.Runtime.
+func (r *Runtime) FuncType(i int) (binary.FuncType, error) {
+	if i < 0 || len(r.store.types) <= i {
+		return binary.FuncType{}, fmt.Errorf("invalid type index: %d", i)
+	}
+	return r.store.types[i], nil
+}
+
+// Table implements types.Runtime.
+func (r *Runtime) Table(i int) (*runtime.TableInst, error) {
+	if i < 0 || len(r.store.tables) <= i {
+		return nil, fmt.Errorf("invalid table index: %d", i)
+	}
+	return r.store.tables[i], nil
+}
+
+// Element implements types.Runtime.
+func (r *Runtime) Element(i int) (*runtime.ElementInst, error) {
+	if i < 0 || len(r.store.elements) <= i {
+		return nil, fmt.Errorf("invalid element index: %d", i)
+	}
+	return r.store.elements[i], nil
+}
+
 // PopCallStack implements types.Runtime.
 func (r *Runtime) PopCallStack() (*runtime.Frame, error) {
-	if len(r.callStack) == 0 {
+	frame, ok := r.callStack.Pop()
+	if !ok {
 		return nil, runtime.ErrEmptyStack
 	}
 
-	return r.callStack.Pop(), nil
+	return frame, nil
 }
 
 // PopStack implements types.Runtime.
 func (r *Runtime) PopStack() (runtime.Value, error) {
-	if len(r.stack) == 0 {
+	v, ok := r.stack.Pop()
+	if !ok {
 		return nil, runtime.ErrEmptyStack
 	}
 
-	return r.stack.Pop(), nil
+	return v, nil
 }
 
 // PushCallStack implements types.Runtime.
@@ -146,7 +253,7 @@ func (r *Runtime) PushStack(value runtime.Value) {
 
 // SplitOffStack implements types.Runtime.
 func (r *Runtime) SplitOffStack(n int) (stack.Stack[runtime.Value], error) {
-	if len(r.stack) < n {
+	if n < 0 || len(r.stack) < n {
 		return nil, runtime.ErrIndexOufOfRange
 	}
 	return r.stack.SplitOff(n), nil
@@ -170,10 +277,7 @@ func (r *Runtime) StackUnwind(stackPointer int, arity int) error {
 		return fmt.Errorf("stack underflow")
 	}
 
-	returns := make([]runtime.Value, 0, arity)
-	for range arity {
-		returns = append(returns, r.stack.Pop())
-	}
+	returns := r.stack.SplitOff(r.stack.Len() - arity)
 
 	r.stack.Drain(stackPointer)
 
@@ -185,53 +289,122 @@ func (r *Runtime) StackUnwind(stackPointer int, arity int) error {
 }
 
 // invokeInternal implements types.Runtime.
-func (r *Runtime) InvokeInternal(f runtime.InternalFuncInst) ([]runtime.Value, error) {
-	arity := len(f.FuncType.Results)
+func (r *Runtime) InvokeInternal(f runtime.InternalFuncInst) (results []runtime.Value, err error) {
+	if f.Instance != nil && f.Instance != runtime.Runtime(r) {
+		// NOTE: the function runs with the stacks and store of the instance that defines it,
+		// so a panic in it must unwind that instance, not only this one.
+		if other, ok := f.Instance.(*Runtime); ok {
+			defer other.withContext(r.context())()
+			defer other.recoverTrap(other.callStack.Len(), other.stack.Len(), &err)
+		}
+		args, err := r.SplitOffStack(r.stack.Len() - len(f.FuncType.Params))
+		if err != nil {
+			return nil, fmt.Errorf("failed to split off arguments: %w", err)
+		}
+		for _, arg := range args {
+			f.Instance.PushStack(arg)
+		}
+		return f.Instance.InvokeInternal(f)
+	}
+
+	return r.run(f, false)
+}
+
+// run calls f, whose arguments are on the stack.
+// If resumable, running out of fuel suspends the call instead of unwinding it.
+func (r *Runtime) run(f runtime.InternalFuncInst, resumable bool) ([]runtime.Value, error) {
+	// NOTE: a host function may call back into this instance while an outer call is in progress,
+	// so only the frames above depth are executed, and a failure unwinds only this call.
+	a := activation{
+		depth:  r.callStack.Len(),
+		bottom: max(r.stack.Len()-len(f.FuncType.Params), 0),
+		arity:  len(f.FuncType.Results),
+	}
 
 	if err := r.PushFrame(f); err != nil {
-		r.Cleanup()
+		r.unwind(a.depth, a.bottom)
 		return nil, fmt.Errorf("failed to push frame: %w", err)
 	}
 
-	if err := r.execute(); err != nil {
-		r.Cleanup()
-		return nil, fmt.Errorf("failed to execute: %w", err)
+	return r.complete(a, resumable)
+}
+
+// complete executes the activation a until it returns its results.
+func (r *Runtime) complete(a activation, resumable bool) ([]runtime.Value, error) {
+	if err := r.execute(a.depth); err != nil {
+		// NOTE: the fuel of another instance is not identical to the error, so its calls are never suspended here.
+		suspend := resumable && err == runtime.ErrOutOfFuel
+
+		// NOTE: the backtrace is taken before the frames are unwound.
+		trap := r.trap(err, a.depth)
+
+		if suspend {
+			r.suspended = &a
+		} else {
+			r.unwind(a.depth, a.bottom)
+		}
+		return nil, fmt.Errorf("failed to execute: %w", trap)
 	}
 
-	if arity < 1 {
+	if a.arity < 1 {
 		return nil, nil
 	}
 
-	if r.stack.Len() < arity {
-		r.Cleanup()
+	if r.stack.Len() < a.bottom+a.arity {
+		r.unwind(a.depth, a.bottom)
 		return nil, fmt.Errorf("stack underflow")
 	}
 
-	returns := make([]runtime.Value, 0, arity)
-	for range arity {
-		returns = append(returns, r.stack.Pop())
-	}
-
-	return returns, nil
+	return r.stack.SplitOff(r.stack.Len() - a.arity), nil
 }
 
 // InvokeExternal implements types.Runtime.
 func (r *Runtime) InvokeExternal(f runtime.ExternalFuncInst) ([]runtime.Value, error) {
-	bottom := r.stack.Len() - len(f.FuncType.Params)
-	args := r.stack.SplitOff(bottom)
+	args, err := r.SplitOffStack(r.stack.Len() - len(f.FuncType.Params))
+	if err != nil {
+		return nil, fmt.Errorf("failed to split off arguments: %w", err)
+	}
 
-	module, ok := r.imports[f.Module]
-	if !ok {
-		return nil, fmt.Errorf("module not found: %s", f.Module)
+	if f.Instance != nil && f.Instance != runtime.Runtime(r) {
+		// NOTE: the function is resolved by the instance that imports it.
+		if other, ok := f.Instance.(*Runtime); ok {
+			defer other.withContext(r.context())()
+		}
+		for _, arg := range args {
+			f.Instance.PushStack(arg)
+		}
+		return f.Instance.InvokeExternal(f)
 	}
-	fn, ok := module[f.Func]
+
+	host, ok := r.imports.lookup(f.Module, f.Func)
 	if !ok {
-		return nil, fmt.Errorf("function not found: %s", f.Func)
+		return nil, fmt.Errorf("%w: %s.%s", runtime.ErrUnknownImport, f.Module, f.Func)
+	}
+
+	results, err := host.Func(newCaller(r.context(), r), args...)
+	if err != nil {
+		return nil, err
 	}
-	return fn(r.store, args...)
+
+	// NOTE: results are pushed onto the stack as is, so they must match the declared type.
+	if len(results) != len(f.FuncType.Results) {
+		return nil, fmt.Errorf("%w: %s.%s returned %d values, expected %d", runtime.ErrHostResultMismatch, f.Module, f.Func, len(results), len(f.FuncType.Results))
+	}
+	for i, result := range results {
+		if result == nil || result.Type() != runtime.ValueType(f.FuncType.Results[i]) {
+			return nil, fmt.Errorf("%w: %s.%s returned %T at %d, expected %v", runtime.ErrHostResultMismatch, f.Module, f.Func, result, i, f.FuncType.Results[i])
+		}
+	}
+
+	return results, nil
 }
 
 func (r *Runtime) PushFrame(f runtime.InternalFuncInst) error {
+	// NOTE: the values pushed by a function are bounded by its body, so the limits are checked only on entry.
+	if r.callStack.Len() >= r.maxCallDepth || r.stack.Len()+len(f.Code.Locals) > r.maxStackSize {
+		return runtime.ErrStackExhausted
+	}
+
 	bottom := r.StackLen() - len(f.FuncType.Params)
 	locals, err := r.SplitOffStack(bottom)
 	if err != nil {
@@ -239,21 +412,17 @@ func (r *Runtime) PushFrame(f runtime.InternalFuncInst) error {
 	}
 
 	for _, local := range f.Code.Locals {
-		switch local {
-		case binary.ValueTypeI32:
-			locals.Push(runtime.ValueI32(0))
-		case binary.ValueTypeI64:
-			locals.Push(runtime.ValueI64(0))
-		case binary.ValueTypeF32:
-			locals.Push(runtime.ValueF32{})
-		case binary.ValueTypeF64:
-			locals.Push(runtime.ValueF64{})
+		v, err := runtime.ZeroValue(local)
+		if err != nil {
+			return fmt.Errorf("failed to initialize local: %w", err)
 		}
+		locals.Push(v)
 	}
 
 	arity := len(f.FuncType.Results)
 
 	frame := runtime.Frame{
+		FuncIndex:      f.Index,
 		ProgramCounter: -1,
 		StackPointer:   r.StackLen(),
 		Instructions:   f.Code.Body,
@@ -266,13 +435,47 @@ func (r *Runtime) PushFrame(f runtime.InternalFuncInst) error {
 	return nil
 }
 
-func (r *Runtime) Cleanup() {
-	r.stack = nil
-	r.callStack = nil
+// trap converts err to a trap with the backtrace of the frames above depth.
+// If err already contains a trap, such as one from a call into another instance,
+// the frames are appended to its backtrace.
+func (r *Runtime) trap(err error, depth int) *runtime.Trap {
+	trace := make([]runtime.TraceFrame, 0, r.callStack.Len()-depth)
+	for i := r.callStack.Len() - 1; i >= depth; i-- {
+		trace = append(trace, runtime.TraceFrame{
+			FuncIndex: r.callStack[i].FuncIndex,
+			Offset:    r.callStack[i].ProgramCounter,
+		})
+	}
+
+	var t *runtime.Trap
+	if errors.As(err, &t) {
+		t.Trace = append(t.Trace, trace...)
+		return t
+	}
+
+	return &runtime.Trap{
+		Code:  runtime.TrapCodeOf(err),
+		Trace: trace,
+		Err:   err,
+	}
 }
 
-func (r *Runtime) execute() error {
-	for len(r.callStack) > 0 {
+// unwind discards the frames above depth and the values above bottom left by a failed call.
+func (r *Runtime) unwind(depth, bottom int) {
+	if depth < r.callStack.Len() {
+		r.callStack.Drain(depth)
+	}
+	if bottom < r.stack.Len() {
+		r.stack.Drain(bottom)
+	}
+}
+
+// execute runs the frames above depth until they return.
+func (r *Runtime) execute(depth int) error {
+	ctx := r.context()
+	done := ctx.Done()
+
+	for r.callStack.Len() > depth {
 		frame := r.callStack[len(r.callStack)-1]
 
 		frame.ProgramCounter++
@@ -281,10 +484,33 @@ func (r *Runtime) execute() error {
 			break
 		}
 
+		pc, calls := frame.ProgramCounter, r.callStack.Len()
+
 		instruction := frame.Instructions[frame.ProgramCounter]
+
+		if r.metered {
+			cost := r.fuelCost(instruction)
+			if r.fuel < cost {
+				// NOTE: the frame is left at the instruction for the backtrace,
+				// and ResumeContext rewinds it to execute the instruction again.
+				return runtime.ErrOutOfFuel
+			}
+			r.fuel -= cost
+		}
+
 		if err := instruction.Execute(r, frame); err != nil {
 			return fmt.Errorf("failed to execute instruction(%T): %w", instruction, err)
 		}
+
+		// NOTE: a backward branch or a call is a safe point to stop at,
+		// and every infinite execution passes through one of them.
+		if done != nil && (frame.ProgramCounter < pc || r.callStack.Len() != calls) {
+			select {
+			case <-done:
+				return fmt.Errorf("%w: %w", runtime.ErrInterrupted, context.Cause(ctx))
+			default:
+			}
+		}
 	}
 
 	return nil
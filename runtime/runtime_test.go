@@ -2,13 +2,23 @@ package runtime_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Warashi/wasmium/instruction"
+	"github.com/Warashi/wasmium/opcode"
 	"github.com/Warashi/wasmium/runtime"
 
+	typesBinary "github.com/Warashi/wasmium/types/binary"
 	typesRuntime "github.com/Warashi/wasmium/types/runtime"
+	"github.com/Warashi/wasmium/validation"
 )
 
 func TestExecuteI32Add(t *testing.T) {
@@ -131,20 +141,24 @@ func TestCallImportedFunc(t *testing.T) {
 		t.FailNow()
 	}
 
-	r, err := runtime.New(bytes.NewReader(b))
-	if err != nil {
-		t.Errorf("failed to create runtime: %v", err)
-		t.FailNow()
-	}
-
-	r.AddImport("env", "add", func(s *runtime.Store, v ...typesRuntime.Value) ([]typesRuntime.Value, error) {
+	double := func(c *runtime.Caller, v ...typesRuntime.Value) ([]typesRuntime.Value, error) {
 		switch arg := v[0].(type) {
 		case typesRuntime.ValueI32:
 			return []typesRuntime.Value{typesRuntime.ValueI32(arg + arg)}, nil
 		default:
 			return nil, fmt.Errorf("unsupported argument type: %T", arg)
 		}
-	})
+	}
+	funcType := typesBinary.FuncType{
+		Params:  []typesBinary.ValueType{typesBinary.ValueTypeI32},
+		Results: []typesBinary.ValueType{typesBinary.ValueTypeI32},
+	}
+
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithImport("env", "add", funcType, double))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
 
 	tests := []struct {
 		a, want int32
@@ -184,14 +198,109 @@ func TestNotFoundImportedFunc(t *testing.T) {
 		t.FailNow()
 	}
 
-	runtime, err := runtime.New(bytes.NewReader(b))
+	if _, err := runtime.New(bytes.NewReader(b)); !errors.Is(err, typesRuntime.ErrUnknownImport) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUnresolvedImports(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/import_all.wasm")
 	if err != nil {
-		t.Errorf("failed to create runtime: %v", err)
+		t.Errorf("failed to load testdata: %v", err)
 		t.FailNow()
 	}
 
-	if _, err := runtime.Call("call_not_found", nil); err == nil {
-		t.Error("unexpected success")
+	_, err = runtime.New(bytes.NewReader(b))
+	if !errors.Is(err, typesRuntime.ErrUnknownImport) {
+		t.Errorf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	// every unresolved import is reported, not only the first one.
+	for _, name := range []string{"env.memory", "env.table", "env.counter", "env.base"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("missing %s in error: %v", name, err)
+		}
+	}
+}
+
+func TestHostFuncType(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/import.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	called := false
+	fn := func(c *runtime.Caller, v ...typesRuntime.Value) ([]typesRuntime.Value, error) {
+		called = true
+		return v, nil
+	}
+
+	tests := []struct {
+		name     string
+		funcType typesBinary.FuncType
+	}{
+		{name: "params", funcType: typesBinary.FuncType{Params: []typesBinary.ValueType{typesBinary.ValueTypeI64}, Results: []typesBinary.ValueType{typesBinary.ValueTypeI32}}},
+		{name: "results", funcType: typesBinary.FuncType{Params: []typesBinary.ValueType{typesBinary.ValueTypeI32}}},
+	}
+
+	for _, test := range tests {
+		_, err := runtime.New(bytes.NewReader(b), runtime.WithImport("env", "add", test.funcType, fn))
+		if !errors.Is(err, typesRuntime.ErrIncompatibleImportType) {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+	}
+
+	if called {
+		t.Errorf("host function called despite type mismatch")
+	}
+}
+
+func TestHostFuncResults(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/import.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	funcType := typesBinary.FuncType{
+		Params:  []typesBinary.ValueType{typesBinary.ValueTypeI32},
+		Results: []typesBinary.ValueType{typesBinary.ValueTypeI32},
+	}
+
+	tests := []struct {
+		name    string
+		results []typesRuntime.Value
+	}{
+		{name: "none", results: nil},
+		{name: "too many", results: []typesRuntime.Value{typesRuntime.ValueI32(1), typesRuntime.ValueI32(2)}},
+		{name: "wrong type", results: []typesRuntime.Value{typesRuntime.ValueI64(1)}},
+		{name: "nil", results: []typesRuntime.Value{nil}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := runtime.New(bytes.NewReader(b), runtime.WithImport("env", "add", funcType, func(c *runtime.Caller, v ...typesRuntime.Value) ([]typesRuntime.Value, error) {
+				return test.results, nil
+			}))
+			if err != nil {
+				t.Errorf("failed to create runtime: %v", err)
+				t.FailNow()
+			}
+
+			if _, err := r.Call("call_add", typesRuntime.ValueI32(1)); !errors.Is(err, typesRuntime.ErrHostResultMismatch) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
 	}
 }
 
@@ -427,3 +536,1759 @@ func TestFib(t *testing.T) {
 		})
 	}
 }
+
+func TestCallIndirect(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/call_indirect.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	double := func(c *runtime.Caller, v ...typesRuntime.Value) ([]typesRuntime.Value, error) {
+		switch arg := v[0].(type) {
+		case typesRuntime.ValueI32:
+			return []typesRuntime.Value{typesRuntime.ValueI32(arg + arg)}, nil
+		default:
+			return nil, fmt.Errorf("unsupported argument type: %T", arg)
+		}
+	}
+	funcType := typesBinary.FuncType{
+		Params:  []typesBinary.ValueType{typesBinary.ValueTypeI32},
+		Results: []typesBinary.ValueType{typesBinary.ValueTypeI32},
+	}
+
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithImport("env", "double", funcType, double))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	tests := []struct {
+		index, arg int32
+		want       int32
+		err        error
+	}{
+		{index: 0, arg: 10, want: 11},
+		{index: 1, arg: 10, want: 20},
+		{index: 2, arg: 10, err: typesRuntime.ErrIndirectCallTypeMismatch},
+		{index: 3, arg: 10, err: typesRuntime.ErrUninitializedElement},
+		{index: 4, arg: 10, err: typesRuntime.ErrUndefinedElement},
+		{index: -1, arg: 10, err: typesRuntime.ErrUndefinedElement},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("table[%d](%d)", test.index, test.arg), func(t *testing.T) {
+			got, err := r.Call("call", typesRuntime.ValueI32(test.arg), typesRuntime.ValueI32(test.index))
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Errorf("expected error %v, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("failed to call function: %v", err)
+				t.FailNow()
+			}
+			if len(got) != 1 {
+				t.Errorf("unexpected number of return values: %d", len(got))
+				t.FailNow()
+			}
+			if got, ok := got[0].(typesRuntime.ValueI32); !ok || got != typesRuntime.ValueI32(test.want) {
+				t.Errorf("unexpected return value: %v", got)
+			}
+		})
+	}
+}
+
+func TestMemoryGrow(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/memory_grow.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	type step struct {
+		grow int32
+		want int32
+	}
+
+	tests := []struct {
+		name  string
+		opts  []runtime.Option
+		steps []step
+		pages uint32
+	}{
+		{
+			name:  "module maximum",
+			steps: []step{{1, 1}, {0, 2}, {2, -1}, {1, 2}, {1, -1}},
+			pages: 3,
+		},
+		{
+			name:  "host limit",
+			opts:  []runtime.Option{runtime.WithMemoryLimitPages(2)},
+			steps: []step{{2, -1}, {1, 1}, {1, -1}},
+			pages: 2,
+		},
+		{
+			name:  "negative delta",
+			steps: []step{{-1, -1}},
+			pages: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := runtime.New(bytes.NewReader(b), test.opts...)
+			if err != nil {
+				t.Errorf("failed to create runtime: %v", err)
+				t.FailNow()
+			}
+
+			memory, err := r.Store().Memory(0)
+			if err != nil {
+				t.Errorf("failed to get memory: %v", err)
+				t.FailNow()
+			}
+
+			for _, step := range test.steps {
+				got, err := r.Call("grow", typesRuntime.ValueI32(step.grow))
+				if err != nil {
+					t.Errorf("failed to call function: %v", err)
+					t.FailNow()
+				}
+				if len(got) != 1 {
+					t.Errorf("unexpected number of return values: %d", len(got))
+					t.FailNow()
+				}
+				if got, ok := got[0].(typesRuntime.ValueI32); !ok || got != typesRuntime.ValueI32(step.want) {
+					t.Errorf("grow(%d): unexpected return value: %v", step.grow, got)
+				}
+			}
+
+			got, err := r.Call("size")
+			if err != nil {
+				t.Errorf("failed to call function: %v", err)
+				t.FailNow()
+			}
+			if got, ok := got[0].(typesRuntime.ValueI32); !ok || got != typesRuntime.ValueI32(test.pages) {
+				t.Errorf("unexpected memory size: %v", got)
+			}
+			if len(memory.Data) != int(test.pages)*runtime.PageSize {
+				t.Errorf("memory handle does not see the resized data: %d bytes", len(memory.Data))
+			}
+		})
+	}
+}
+
+func TestInteger(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/integer.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	i32 := func(v int32) typesRuntime.Value { return typesRuntime.ValueI32(v) }
+	i64 := func(v int64) typesRuntime.Value { return typesRuntime.ValueI64(v) }
+
+	tests := []struct {
+		name string
+		args []typesRuntime.Value
+		want typesRuntime.Value
+		err  error
+	}{
+		{name: "i32.div_s", args: []typesRuntime.Value{i32(-7), i32(2)}, want: i32(-3)},
+		{name: "i32.div_s", args: []typesRuntime.Value{i32(1), i32(0)}, err: typesRuntime.ErrIntegerDivideByZero},
+		{name: "i32.div_s", args: []typesRuntime.Value{i32(math.MinInt32), i32(-1)}, err: typesRuntime.ErrIntegerOverflow},
+		{name: "i32.div_u", args: []typesRuntime.Value{i32(-1), i32(2)}, want: i32(math.MaxInt32)},
+		{name: "i32.rem_s", args: []typesRuntime.Value{i32(-7), i32(2)}, want: i32(-1)},
+		{name: "i32.rem_s", args: []typesRuntime.Value{i32(math.MinInt32), i32(-1)}, want: i32(0)},
+		{name: "i32.rem_u", args: []typesRuntime.Value{i32(1), i32(0)}, err: typesRuntime.ErrIntegerDivideByZero},
+		{name: "i32.shr_u", args: []typesRuntime.Value{i32(-1), i32(33)}, want: i32(math.MaxInt32)},
+		{name: "i32.rotl", args: []typesRuntime.Value{i32(math.MinInt32), i32(1)}, want: i32(1)},
+		{name: "i32.clz", args: []typesRuntime.Value{i32(1)}, want: i32(31)},
+		{name: "i32.ge_u", args: []typesRuntime.Value{i32(-1), i32(1)}, want: i32(1)},
+		{name: "i64.div_s", args: []typesRuntime.Value{i64(math.MinInt64), i64(-1)}, err: typesRuntime.ErrIntegerOverflow},
+		{name: "i64.rem_s", args: []typesRuntime.Value{i64(7), i64(0)}, err: typesRuntime.ErrIntegerDivideByZero},
+		{name: "i64.extend_i32_u", args: []typesRuntime.Value{i32(-1)}, want: i64(math.MaxUint32)},
+		{name: "i32.wrap_i64", args: []typesRuntime.Value{i64(math.MaxUint32 + 2)}, want: i32(1)},
+		{name: "i32.extend8_s", args: []typesRuntime.Value{i32(0x80)}, want: i32(-128)},
+		{name: "i32.extend8_s", args: []typesRuntime.Value{i32(0x17f)}, want: i32(127)},
+		{name: "i32.extend16_s", args: []typesRuntime.Value{i32(0x8000)}, want: i32(-32768)},
+		{name: "i64.extend8_s", args: []typesRuntime.Value{i64(0xff)}, want: i64(-1)},
+		{name: "i64.extend16_s", args: []typesRuntime.Value{i64(0x17fff)}, want: i64(32767)},
+		{name: "i64.extend32_s", args: []typesRuntime.Value{i64(0x80000000)}, want: i64(math.MinInt32)},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s%v", test.name, test.args), func(t *testing.T) {
+			got, err := r.Call(test.name, test.args...)
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Errorf("expected error %v, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("failed to call function: %v", err)
+				t.FailNow()
+			}
+			if len(got) != 1 {
+				t.Errorf("unexpected number of return values: %d", len(got))
+				t.FailNow()
+			}
+			if got[0] != test.want {
+				t.Errorf("unexpected return value: %v", got[0])
+			}
+		})
+	}
+}
+
+func TestFloat(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/float.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	f32 := func(v float32) typesRuntime.Value { return typesRuntime.NewValueF32(v) }
+	f64 := func(v float64) typesRuntime.Value { return typesRuntime.NewValueF64(v) }
+	negZero := math.Copysign(0, -1)
+	nan32 := float32(math.NaN())
+
+	tests := []struct {
+		name string
+		args []typesRuntime.Value
+		want typesRuntime.Value
+		nan  bool
+	}{
+		{name: "f32.add", args: []typesRuntime.Value{f32(1.5), f32(2.25)}, want: f32(3.75)},
+		{name: "f32.add", args: []typesRuntime.Value{f32(nan32), f32(1)}, nan: true},
+		{name: "f32.min", args: []typesRuntime.Value{f32(0), f32(float32(negZero))}, want: f32(float32(negZero))},
+		{name: "f32.min", args: []typesRuntime.Value{f32(1), f32(nan32)}, nan: true},
+		{name: "f32.neg", args: []typesRuntime.Value{f32(0)}, want: f32(float32(negZero))},
+		{name: "f32.nearest", args: []typesRuntime.Value{f32(2.5)}, want: f32(2)},
+		{name: "f32.nearest", args: []typesRuntime.Value{f32(-0.5)}, want: f32(float32(negZero))},
+		{name: "f32.eq", args: []typesRuntime.Value{f32(0), f32(float32(negZero))}, want: typesRuntime.ValueI32(1)},
+		{name: "f32.eq", args: []typesRuntime.Value{f32(nan32), f32(nan32)}, want: typesRuntime.ValueI32(0)},
+		{name: "f64.max", args: []typesRuntime.Value{f64(negZero), f64(0)}, want: f64(0)},
+		{name: "f64.max", args: []typesRuntime.Value{f64(math.Inf(1)), f64(math.NaN())}, nan: true},
+		{name: "f64.abs", args: []typesRuntime.Value{f64(math.Float64frombits(0xfff0000000000001))}, want: f64(math.Float64frombits(0x7ff0000000000001))},
+		{name: "f64.copysign", args: []typesRuntime.Value{f64(2), f64(negZero)}, want: f64(-2)},
+		{name: "f64.sqrt", args: []typesRuntime.Value{f64(-1)}, nan: true},
+		{name: "f64.convert_i64_u", args: []typesRuntime.Value{typesRuntime.ValueI64(-1)}, want: f64(math.MaxUint64)},
+		{name: "f32.demote_f64", args: []typesRuntime.Value{f64(math.MaxFloat64)}, want: f32(float32(math.Inf(1)))},
+		{name: "i32.reinterpret_f32", args: []typesRuntime.Value{f32(float32(negZero))}, want: typesRuntime.ValueI32(math.MinInt32)},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s%v", test.name, test.args), func(t *testing.T) {
+			got, err := r.Call(test.name, test.args...)
+			if err != nil {
+				t.Errorf("failed to call function: %v", err)
+				t.FailNow()
+			}
+			if len(got) != 1 {
+				t.Errorf("unexpected number of return values: %d", len(got))
+				t.FailNow()
+			}
+			if test.nan {
+				switch v := got[0].(type) {
+				case typesRuntime.ValueF32:
+					if !math.IsNaN(float64(v.Float32())) {
+						t.Errorf("unexpected return value: %v", v.Float32())
+					}
+				case typesRuntime.ValueF64:
+					if !math.IsNaN(v.Float64()) {
+						t.Errorf("unexpected return value: %v", v.Float64())
+					}
+				default:
+					t.Errorf("unexpected return value: %v", v)
+				}
+				return
+			}
+			if got[0] != test.want {
+				t.Errorf("unexpected return value: %v", got[0])
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/truncate.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	f32 := func(v float32) typesRuntime.Value { return typesRuntime.NewValueF32(v) }
+	f64 := func(v float64) typesRuntime.Value { return typesRuntime.NewValueF64(v) }
+
+	tests := []struct {
+		name string
+		arg  typesRuntime.Value
+		want typesRuntime.Value
+		err  error
+	}{
+		{name: "i32.trunc_f32_s", arg: f32(-1.9), want: typesRuntime.ValueI32(-1)},
+		{name: "i32.trunc_f32_s", arg: f32(-2147483648), want: typesRuntime.ValueI32(math.MinInt32)},
+		{name: "i32.trunc_f32_s", arg: f32(2147483648), err: typesRuntime.ErrIntegerOverflow},
+		{name: "i32.trunc_f32_s", arg: f32(float32(math.NaN())), err: typesRuntime.ErrInvalidConversionToInteger},
+		{name: "i32.trunc_f64_u", arg: f64(4294967295.9), want: typesRuntime.ValueI32(-1)},
+		{name: "i32.trunc_f64_u", arg: f64(-0.9), want: typesRuntime.ValueI32(0)},
+		{name: "i32.trunc_f64_u", arg: f64(-1), err: typesRuntime.ErrIntegerOverflow},
+		{name: "i64.trunc_f64_s", arg: f64(-9223372036854775808), want: typesRuntime.ValueI64(math.MinInt64)},
+		{name: "i64.trunc_f64_s", arg: f64(9223372036854775808), err: typesRuntime.ErrIntegerOverflow},
+		{name: "i64.trunc_f32_u", arg: f32(float32(math.Inf(1))), err: typesRuntime.ErrIntegerOverflow},
+		{name: "i64.trunc_f32_u", arg: f32(float32(math.NaN())), err: typesRuntime.ErrInvalidConversionToInteger},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s(%v)", test.name, test.arg), func(t *testing.T) {
+			got, err := r.Call(test.name, test.arg)
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Errorf("expected error %v, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("failed to call function: %v", err)
+				t.FailNow()
+			}
+			if len(got) != 1 {
+				t.Errorf("unexpected number of return values: %d", len(got))
+				t.FailNow()
+			}
+			if got[0] != test.want {
+				t.Errorf("unexpected return value: %v", got[0])
+			}
+		})
+	}
+}
+
+func TestMemoryBulk(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/memory_bulk.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	memory, err := r.Store().Memory(0)
+	if err != nil {
+		t.Errorf("failed to get memory: %v", err)
+		t.FailNow()
+	}
+
+	i32 := func(v int32) typesRuntime.Value { return typesRuntime.ValueI32(v) }
+
+	// steps share the same instance and run in order.
+	steps := []struct {
+		name   string
+		args   []typesRuntime.Value
+		err    error
+		offset int
+		want   string
+	}{
+		{name: "init", args: []typesRuntime.Value{i32(10), i32(1), i32(4)}, offset: 10, want: "ello"},
+		{name: "init", args: []typesRuntime.Value{i32(0), i32(3), i32(3)}, err: typesRuntime.ErrMemoryOutOfBounds, offset: 0, want: "wasm"},
+		{name: "copy", args: []typesRuntime.Value{i32(2), i32(0), i32(4)}, offset: 0, want: "wawasm"},
+		{name: "copy", args: []typesRuntime.Value{i32(10), i32(12), i32(2)}, offset: 10, want: "lolo"},
+		{name: "fill", args: []typesRuntime.Value{i32(runtime.PageSize - 2), i32('x'), i32(2)}, offset: runtime.PageSize - 3, want: "\x00xx"},
+		{name: "fill", args: []typesRuntime.Value{i32(runtime.PageSize - 1), i32('y'), i32(2)}, err: typesRuntime.ErrMemoryOutOfBounds, offset: runtime.PageSize - 3, want: "\x00xx"},
+		{name: "drop"},
+		{name: "init", args: []typesRuntime.Value{i32(0), i32(0), i32(0)}, offset: 0, want: "wawasm"},
+		{name: "init", args: []typesRuntime.Value{i32(0), i32(0), i32(1)}, err: typesRuntime.ErrMemoryOutOfBounds, offset: 0, want: "wawasm"},
+	}
+
+	for _, step := range steps {
+		_, err := r.Call(step.name, step.args...)
+		if step.err != nil {
+			if !errors.Is(err, step.err) {
+				t.Errorf("%s%v: expected error %v, got %v", step.name, step.args, step.err, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s%v: failed to call function: %v", step.name, step.args, err)
+			t.FailNow()
+		}
+		if got := string(memory.Data[step.offset : step.offset+len(step.want)]); got != step.want {
+			t.Errorf("%s%v: unexpected memory: %q", step.name, step.args, got)
+		}
+	}
+}
+
+func TestMultiMemory(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/multi_memory.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	i32 := func(v int32) typesRuntime.Value { return typesRuntime.ValueI32(v) }
+
+	// store writes to memory 1, which load_b reads 4 bytes after its address.
+	steps := []struct {
+		name string
+		args []typesRuntime.Value
+		want []typesRuntime.Value
+	}{
+		{name: "store", args: []typesRuntime.Value{i32(8), i32(42)}},
+		{name: "load_b", args: []typesRuntime.Value{i32(4)}, want: []typesRuntime.Value{i32(42)}},
+		{name: "load_a", args: []typesRuntime.Value{i32(8)}, want: []typesRuntime.Value{i32(0)}},
+	}
+
+	for _, step := range steps {
+		got, err := r.Call(step.name, step.args...)
+		if err != nil {
+			t.Errorf("%s%v: failed to call function: %v", step.name, step.args, err)
+			t.FailNow()
+		}
+		if !slices.Equal(got, step.want) {
+			t.Errorf("%s%v: unexpected result: %v", step.name, step.args, got)
+		}
+	}
+
+	memory, err := r.Memory(1)
+	if err != nil {
+		t.Errorf("failed to get memory: %v", err)
+		t.FailNow()
+	}
+	if memory.Data[8] != 42 {
+		t.Errorf("unexpected memory: %v", memory.Data[8:12])
+	}
+}
+
+func TestTableOps(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/table_ops.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	i32 := func(v int32) typesRuntime.Value { return typesRuntime.ValueI32(v) }
+	ext := func(v any) typesRuntime.Value { return typesRuntime.ValueExternRef{Ref: v} }
+	null := typesRuntime.ValueExternRef{}
+
+	// steps share the same instance and run in order.
+	steps := []struct {
+		name string
+		args []typesRuntime.Value
+		err  error
+		want []typesRuntime.Value
+	}{
+		{name: "size", want: []typesRuntime.Value{i32(2)}},
+		{name: "set", args: []typesRuntime.Value{i32(0), ext("a")}},
+		{name: "get", args: []typesRuntime.Value{i32(0)}, want: []typesRuntime.Value{ext("a")}},
+		{name: "get", args: []typesRuntime.Value{i32(1)}, want: []typesRuntime.Value{null}},
+		{name: "get", args: []typesRuntime.Value{i32(2)}, err: typesRuntime.ErrTableOutOfBounds},
+		{name: "set", args: []typesRuntime.Value{i32(2), ext("a")}, err: typesRuntime.ErrTableOutOfBounds},
+		{name: "grow", args: []typesRuntime.Value{ext("b"), i32(2)}, want: []typesRuntime.Value{i32(2)}},
+		{name: "size", want: []typesRuntime.Value{i32(4)}},
+		{name: "get", args: []typesRuntime.Value{i32(3)}, want: []typesRuntime.Value{ext("b")}},
+		{name: "grow", args: []typesRuntime.Value{null, i32(typesRuntime.MaxTableSize)}, want: []typesRuntime.Value{i32(-1)}},
+		{name: "fill", args: []typesRuntime.Value{i32(1), ext("c"), i32(2)}},
+		{name: "get", args: []typesRuntime.Value{i32(2)}, want: []typesRuntime.Value{ext("c")}},
+		{name: "fill", args: []typesRuntime.Value{i32(3), ext("d"), i32(2)}, err: typesRuntime.ErrTableOutOfBounds},
+		{name: "copy", args: []typesRuntime.Value{i32(0), i32(2), i32(2)}},
+		{name: "get", args: []typesRuntime.Value{i32(1)}, want: []typesRuntime.Value{ext("b")}},
+		{name: "copy", args: []typesRuntime.Value{i32(3), i32(0), i32(2)}, err: typesRuntime.ErrTableOutOfBounds},
+		{name: "is_null", args: []typesRuntime.Value{i32(1)}, want: []typesRuntime.Value{i32(1)}},
+		{name: "init", args: []typesRuntime.Value{i32(1), i32(0), i32(2)}},
+		{name: "is_null", args: []typesRuntime.Value{i32(1)}, want: []typesRuntime.Value{i32(0)}},
+		{name: "call", args: []typesRuntime.Value{i32(2)}, want: []typesRuntime.Value{i32(2)}},
+		{name: "init", args: []typesRuntime.Value{i32(3), i32(1), i32(2)}, err: typesRuntime.ErrTableOutOfBounds},
+		{name: "ref_func", want: []typesRuntime.Value{i32(0)}},
+		{name: "drop"},
+		{name: "init", args: []typesRuntime.Value{i32(0), i32(0), i32(0)}},
+		{name: "init", args: []typesRuntime.Value{i32(0), i32(0), i32(1)}, err: typesRuntime.ErrTableOutOfBounds},
+	}
+
+	for _, step := range steps {
+		got, err := r.Call(step.name, step.args...)
+		if step.err != nil {
+			if !errors.Is(err, step.err) {
+				t.Errorf("%s%v: expected error %v, got %v", step.name, step.args, step.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s%v: failed to call function: %v", step.name, step.args, err)
+			t.FailNow()
+		}
+		if !slices.Equal(got, step.want) {
+			t.Errorf("%s%v: unexpected result: %v", step.name, step.args, got)
+		}
+	}
+}
+
+func TestTableRefType(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/table_ops.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	// table 1 holds funcref, so an externref is rejected.
+	tests := []struct {
+		name string
+		inst typesRuntime.Instruction
+		args []typesRuntime.Value
+	}{
+		{name: "table.set", inst: &instruction.TableSet{TableIndex: 1}, args: []typesRuntime.Value{typesRuntime.ValueI32(0), typesRuntime.ValueExternRef{}}},
+		{name: "table.grow", inst: &instruction.FCPrefix{FC: &instruction.FCTableGrow{TableIndex: 1}}, args: []typesRuntime.Value{typesRuntime.ValueExternRef{}, typesRuntime.ValueI32(1)}},
+		{name: "table.fill", inst: &instruction.FCPrefix{FC: &instruction.FCTableFill{TableIndex: 1}}, args: []typesRuntime.Value{typesRuntime.ValueI32(0), typesRuntime.ValueExternRef{}, typesRuntime.ValueI32(1)}},
+	}
+
+	for _, test := range tests {
+		for _, arg := range test.args {
+			r.PushStack(arg)
+		}
+		if err := test.inst.Execute(r, nil); !errors.Is(err, typesRuntime.ErrTypeMismatch) {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+	}
+
+	// ref.null takes only a reference type.
+	if err := new(instruction.RefNull).ReadOperandsFrom(bytes.NewReader([]byte{0x7f})); err == nil {
+		t.Errorf("expected error for ref.null i32, got nil")
+	}
+}
+
+func TestMultiValue(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/multi_value.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	i32 := func(v int32) typesRuntime.Value { return typesRuntime.ValueI32(v) }
+	i64 := func(v int64) typesRuntime.Value { return typesRuntime.ValueI64(v) }
+
+	tests := []struct {
+		name string
+		args []typesRuntime.Value
+		want []typesRuntime.Value
+	}{
+		{name: "swap", args: []typesRuntime.Value{i32(1), i32(2)}, want: []typesRuntime.Value{i32(2), i32(1)}},
+		{name: "block", args: []typesRuntime.Value{i32(3), i32(4)}, want: []typesRuntime.Value{i32(7), i32(3)}},
+		{name: "if", args: []typesRuntime.Value{i32(5), i32(1)}, want: []typesRuntime.Value{i32(5), i32(1)}},
+		{name: "if", args: []typesRuntime.Value{i32(5), i32(0)}, want: []typesRuntime.Value{i32(5), i32(2)}},
+		{name: "sum", args: []typesRuntime.Value{i32(10)}, want: []typesRuntime.Value{i32(55)}},
+		{name: "br", want: []typesRuntime.Value{i32(1), i32(2), i64(3)}},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s%v", test.name, test.args), func(t *testing.T) {
+			got, err := r.Call(test.name, test.args...)
+			if err != nil {
+				t.Errorf("failed to call function: %v", err)
+				t.FailNow()
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("unexpected result: %v", got)
+			}
+		})
+	}
+}
+
+func TestStart(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/start.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	var called int
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithImport("env", "init", typesBinary.FuncType{Results: []typesBinary.ValueType{typesBinary.ValueTypeI32}}, func(c *runtime.Caller, v ...typesRuntime.Value) ([]typesRuntime.Value, error) {
+		called++
+		return []typesRuntime.Value{typesRuntime.ValueI32(42)}, nil
+	}))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	if called != 1 {
+		t.Errorf("unexpected number of start calls: %d", called)
+	}
+
+	memory, err := r.Store().Memory(0)
+	if err != nil {
+		t.Errorf("failed to get memory: %v", err)
+		t.FailNow()
+	}
+
+	// the start function runs after data segments are copied into memory.
+	if got := string(memory.Data[0:5]); got != "Jello" {
+		t.Errorf("unexpected memory: %q", got)
+	}
+
+	got, err := r.Call("get")
+	if err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	if len(got) != 1 {
+		t.Errorf("unexpected number of return values: %d", len(got))
+		t.FailNow()
+	}
+	if got, ok := got[0].(typesRuntime.ValueI32); !ok || got != 42 {
+		t.Errorf("unexpected return value: %v", got)
+	}
+}
+
+func TestInstantiateError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		file string
+		// want is a part of the expected error message, if set.
+		want string
+	}{
+		{name: "missing import for start", file: "../testdata/start.wasm"},
+		{name: "trap in start", file: "../testdata/start_trap.wasm"},
+		{name: "segment out of bounds", file: "../testdata/segment_out_of_bounds.wasm"},
+		{name: "segment offset is unsigned", file: "../testdata/segment_offset_unsigned.wasm", want: "offset 2147483648"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := os.ReadFile(test.file)
+			if err != nil {
+				t.Errorf("failed to load testdata: %v", err)
+				t.FailNow()
+			}
+
+			r, err := runtime.New(bytes.NewReader(b))
+			if err == nil {
+				t.Errorf("expected error, got nil")
+			} else if !strings.Contains(err.Error(), test.want) {
+				t.Errorf("expected error containing %q, got %v", test.want, err)
+			}
+			if r != nil {
+				t.Errorf("expected nil runtime, got %v", r)
+			}
+		})
+	}
+}
+
+func TestImportAll(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/import_all.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	memory := typesRuntime.NewMemoryInst(typesBinary.Limits{Min: 1})
+	table := typesRuntime.NewTableInst(typesBinary.TableType{ElementType: typesBinary.RefTypeFunc, Limits: typesBinary.Limits{Min: 3}})
+	counter := &typesRuntime.GlobalInst{Value: typesRuntime.ValueI32(0), Mutable: true}
+	base := &typesRuntime.GlobalInst{Value: typesRuntime.ValueI32(5)}
+
+	r, err := runtime.New(bytes.NewReader(b),
+		runtime.WithMemory("env", "memory", memory),
+		runtime.WithTable("env", "table", table),
+		runtime.WithGlobal("env", "counter", counter),
+		runtime.WithGlobal("env", "base", base),
+	)
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	if _, err := r.Call("store", typesRuntime.ValueI32(8), typesRuntime.ValueI32(0x01020304)); err != nil {
+		t.Errorf("failed to call store: %v", err)
+		t.FailNow()
+	}
+	if got := memory.Data[8:12]; !bytes.Equal(got, []byte{0x04, 0x03, 0x02, 0x01}) {
+		t.Errorf("unexpected memory: %v", got)
+	}
+
+	for range 2 {
+		if _, err := r.Call("incr"); err != nil {
+			t.Errorf("failed to call incr: %v", err)
+			t.FailNow()
+		}
+	}
+	if got := counter.Value; got != typesRuntime.ValueI32(2) {
+		t.Errorf("unexpected counter: %v", got)
+	}
+
+	tests := []struct {
+		name string
+		want int32
+	}{
+		// imported globals and tables come before the ones defined by the module.
+		{name: "sum", want: 105},
+		{name: "table_size", want: 3},
+		{name: "own_table_size", want: 1},
+	}
+
+	for _, test := range tests {
+		got, err := r.Call(test.name)
+		if err != nil {
+			t.Errorf("%s: failed to call function: %v", test.name, err)
+			t.FailNow()
+		}
+		if len(got) != 1 {
+			t.Errorf("%s: unexpected number of return values: %d", test.name, len(got))
+			t.FailNow()
+		}
+		if got, ok := got[0].(typesRuntime.ValueI32); !ok || got != typesRuntime.ValueI32(test.want) {
+			t.Errorf("%s: unexpected return value: %v", test.name, got)
+		}
+	}
+}
+
+func TestImportMismatch(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/import_all.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	memory := func() runtime.Option {
+		return runtime.WithMemory("env", "memory", typesRuntime.NewMemoryInst(typesBinary.Limits{Min: 1}))
+	}
+	table := func(min uint32) runtime.Option {
+		return runtime.WithTable("env", "table", typesRuntime.NewTableInst(typesBinary.TableType{ElementType: typesBinary.RefTypeFunc, Limits: typesBinary.Limits{Min: min}}))
+	}
+	counter := func(mutable bool) runtime.Option {
+		return runtime.WithGlobal("env", "counter", &typesRuntime.GlobalInst{Value: typesRuntime.ValueI32(0), Mutable: mutable})
+	}
+	base := func(v typesRuntime.Value) runtime.Option {
+		return runtime.WithGlobal("env", "base", &typesRuntime.GlobalInst{Value: v})
+	}
+
+	tests := []struct {
+		name string
+		opts []runtime.Option
+	}{
+		{name: "missing memory", opts: []runtime.Option{table(2), counter(true), base(typesRuntime.ValueI32(0))}},
+		{name: "table too small", opts: []runtime.Option{memory(), table(1), counter(true), base(typesRuntime.ValueI32(0))}},
+		{name: "immutable global", opts: []runtime.Option{memory(), table(2), counter(false), base(typesRuntime.ValueI32(0))}},
+		{name: "global type", opts: []runtime.Option{memory(), table(2), counter(true), base(typesRuntime.ValueI64(0))}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := runtime.New(bytes.NewReader(b), test.opts...); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestCompiledModule(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/counter.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	c, err := runtime.Compile(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to compile module: %v", err)
+		t.FailNow()
+	}
+
+	// instances share the compiled module but never their state.
+	for n := range 8 {
+		t.Run(fmt.Sprintf("instance%d", n), func(t *testing.T) {
+			t.Parallel()
+
+			r, err := c.Instantiate()
+			if err != nil {
+				t.Errorf("failed to instantiate: %v", err)
+				t.FailNow()
+			}
+
+			for i := range n + 1 {
+				got, err := r.Call("incr")
+				if err != nil {
+					t.Errorf("failed to call function: %v", err)
+					t.FailNow()
+				}
+				if len(got) != 1 {
+					t.Errorf("unexpected number of return values: %d", len(got))
+					t.FailNow()
+				}
+				if got, ok := got[0].(typesRuntime.ValueI32); !ok || got != typesRuntime.ValueI32(i+1) {
+					t.Errorf("unexpected return value: %v", got)
+				}
+			}
+
+			memory, err := r.Store().Memory(0)
+			if err != nil {
+				t.Errorf("failed to get memory: %v", err)
+				t.FailNow()
+			}
+			if got := memory.Data[0]; got != byte(n+1) {
+				t.Errorf("unexpected memory: %d", got)
+			}
+		})
+	}
+}
+
+func TestLinker(t *testing.T) {
+	t.Parallel()
+
+	compile := func(file string) *runtime.CompiledModule {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Errorf("failed to load testdata: %v", err)
+			t.FailNow()
+		}
+		c, err := runtime.Compile(bytes.NewReader(b))
+		if err != nil {
+			t.Errorf("failed to compile module: %v", err)
+			t.FailNow()
+		}
+		return c
+	}
+
+	linker := runtime.NewLinker()
+
+	a, err := linker.Instantiate(compile("../testdata/linker_a.wasm"))
+	if err != nil {
+		t.Errorf("failed to instantiate a: %v", err)
+		t.FailNow()
+	}
+	linker.Register("a", a)
+
+	b, err := linker.Instantiate(compile("../testdata/linker_b.wasm"))
+	if err != nil {
+		t.Errorf("failed to instantiate b: %v", err)
+		t.FailNow()
+	}
+
+	i32 := func(v int32) typesRuntime.Value { return typesRuntime.ValueI32(v) }
+
+	// steps share the same instances and run in order.
+	steps := []struct {
+		instance *runtime.Runtime
+		name     string
+		args     []typesRuntime.Value
+		want     []typesRuntime.Value
+	}{
+		{instance: b, name: "call_get_g", want: []typesRuntime.Value{i32(10)}},
+		{instance: b, name: "set_g", args: []typesRuntime.Value{i32(42)}},
+		{instance: a, name: "get_g", want: []typesRuntime.Value{i32(42)}},
+		{instance: b, name: "call_get_g", want: []typesRuntime.Value{i32(42)}},
+		{instance: b, name: "call_indirect", want: []typesRuntime.Value{i32(42)}},
+		{instance: b, name: "store", args: []typesRuntime.Value{i32(4), i32(7)}},
+		{instance: a, name: "load", args: []typesRuntime.Value{i32(4)}, want: []typesRuntime.Value{i32(7)}},
+	}
+
+	for _, step := range steps {
+		got, err := step.instance.Call(step.name, step.args...)
+		if err != nil {
+			t.Errorf("%s%v: failed to call function: %v", step.name, step.args, err)
+			t.FailNow()
+		}
+		if !slices.Equal(got, step.want) {
+			t.Errorf("%s%v: unexpected result: %v", step.name, step.args, got)
+		}
+	}
+
+	// options do not override the exports of a registered instance.
+	override, err := linker.Instantiate(compile("../testdata/linker_b.wasm"),
+		runtime.WithMemory("a", "memory", typesRuntime.NewMemoryInst(typesBinary.Limits{Min: 1})),
+		runtime.WithTable("a", "table", typesRuntime.NewTableInst(typesBinary.TableType{ElementType: typesBinary.RefTypeFunc, Limits: typesBinary.Limits{Min: 2}})),
+		runtime.WithGlobal("a", "g", &typesRuntime.GlobalInst{Value: i32(0), Mutable: true}),
+	)
+	if err != nil {
+		t.Errorf("failed to instantiate b: %v", err)
+		t.FailNow()
+	}
+	overrides := []struct {
+		instance *runtime.Runtime
+		name     string
+		args     []typesRuntime.Value
+		want     []typesRuntime.Value
+	}{
+		{instance: override, name: "set_g", args: []typesRuntime.Value{i32(43)}},
+		{instance: a, name: "get_g", want: []typesRuntime.Value{i32(43)}},
+		{instance: override, name: "call_indirect", want: []typesRuntime.Value{i32(43)}},
+		{instance: override, name: "store", args: []typesRuntime.Value{i32(8), i32(9)}},
+		{instance: a, name: "load", args: []typesRuntime.Value{i32(8)}, want: []typesRuntime.Value{i32(9)}},
+	}
+	for _, step := range overrides {
+		got, err := step.instance.Call(step.name, step.args...)
+		if err != nil {
+			t.Errorf("%s%v: failed to call function: %v", step.name, step.args, err)
+			t.FailNow()
+		}
+		if !slices.Equal(got, step.want) {
+			t.Errorf("%s%v: unexpected result: %v", step.name, step.args, got)
+		}
+	}
+
+	for _, file := range []string{"../testdata/linker_incompatible.wasm", "../testdata/linker_unknown.wasm"} {
+		if _, err := linker.Instantiate(compile(file)); err == nil {
+			t.Errorf("%s: expected error, got nil", file)
+		}
+	}
+}
+
+func TestHostFunc(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/typed.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	errNegative := errors.New("negative")
+	scale := func(ctx context.Context, x int32, factor int64) (float64, error) {
+		if x < 0 {
+			return 0, errNegative
+		}
+		return float64(x) * float64(factor) / 2, nil
+	}
+
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithHostFunc("env", "scale", scale))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	got, err := r.Call("call_scale", typesRuntime.ValueI32(3), typesRuntime.ValueI64(5))
+	if err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.Value{typesRuntime.NewValueF64(7.5)}; !slices.Equal(got, want) {
+		t.Errorf("unexpected return value: %v", got)
+	}
+
+	if _, err := r.Call("call_scale", typesRuntime.ValueI32(-1), typesRuntime.ValueI64(5)); !errors.Is(err, errNegative) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// the inferred type is checked against the import.
+	mismatch := func(x int32, factor int32) float64 { return 0 }
+	if _, err := runtime.New(bytes.NewReader(b), runtime.WithHostFunc("env", "scale", mismatch)); !errors.Is(err, typesRuntime.ErrIncompatibleImportType) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	unsupported := func(x string) float64 { return 0 }
+	if _, err := runtime.New(bytes.NewReader(b), runtime.WithHostFunc("env", "scale", unsupported)); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestExportedFunc(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/typed.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithHostFunc("env", "scale", func(x int32, factor int64) float64 {
+		return float64(x) * float64(factor)
+	}))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	divU, err := runtime.ExportedFunc[func(uint32, uint32) (uint32, error)](r, "div_u")
+	if err != nil {
+		t.Errorf("failed to bind export: %v", err)
+		t.FailNow()
+	}
+	if got, err := divU(math.MaxUint32, 2); err != nil || got != math.MaxUint32/2 {
+		t.Errorf("unexpected result: %v, %v", got, err)
+	}
+	if _, err := divU(1, 0); !errors.Is(err, typesRuntime.ErrIntegerDivideByZero) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	split, err := runtime.ExportedFunc[func(float32) (float32, bool, error)](r, "split")
+	if err != nil {
+		t.Errorf("failed to bind export: %v", err)
+		t.FailNow()
+	}
+	if f, negative, err := split(-1.5); err != nil || f != -1.5 || !negative {
+		t.Errorf("unexpected result: %v, %v, %v", f, negative, err)
+	}
+
+	scale, err := runtime.ExportedFunc[func(int32, int64) (float64, error)](r, "call_scale")
+	if err != nil {
+		t.Errorf("failed to bind export: %v", err)
+		t.FailNow()
+	}
+	if got, err := scale(3, 5); err != nil || got != 15 {
+		t.Errorf("unexpected result: %v, %v", got, err)
+	}
+
+	if _, err := runtime.ExportedFunc[func(int32, int32) (int32, error)](r, "call_scale"); err == nil {
+		t.Errorf("expected type mismatch, got nil")
+	}
+	if _, err := runtime.ExportedFunc[func(uint32, uint32) uint32](r, "div_u"); err == nil {
+		t.Errorf("expected missing error result, got nil")
+	}
+	if _, err := runtime.ExportedFunc[func() error](r, "not_found"); err == nil {
+		t.Errorf("expected missing export, got nil")
+	}
+}
+
+func TestCaller(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/caller.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	peek := func(c *runtime.Caller, addr int32) (int32, error) {
+		if c.Context() == nil {
+			return 0, errors.New("missing context")
+		}
+		if _, ok := c.Memory("memory"); ok {
+			return 0, errors.New("unexpected memory export")
+		}
+		if _, ok := c.Func("run"); !ok {
+			return 0, errors.New("missing function export")
+		}
+
+		memory, ok := c.Memory("mem")
+		if !ok {
+			return 0, errors.New("missing memory export")
+		}
+		base, ok := c.Global("base")
+		if !ok {
+			return 0, errors.New("missing global export")
+		}
+
+		return int32(memory.Data[addr]) + int32(base.Value.(typesRuntime.ValueI32)), nil
+	}
+
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithHostFunc("env", "peek", peek))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	got, err := r.Call("run", typesRuntime.ValueI32(8))
+	if err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.Value{typesRuntime.ValueI32(42 + 7)}; !slices.Equal(got, want) {
+		t.Errorf("unexpected return value: %v", got)
+	}
+}
+
+func TestReentrantCall(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/reentrant.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	// host allocates a buffer in the guest, ignores a trapping call,
+	// and recurses into the guest until n reaches zero.
+	host := func(c *runtime.Caller, n int32) (int32, error) {
+		v, err := c.Call("malloc", typesRuntime.ValueI32(8))
+		if err != nil {
+			return 0, fmt.Errorf("failed to call malloc: %w", err)
+		}
+		ptr := int32(v[0].(typesRuntime.ValueI32))
+
+		memory, ok := c.Memory("memory")
+		if !ok {
+			return 0, errors.New("missing memory export")
+		}
+		memory.Data[ptr] = byte(n)
+
+		if _, err := c.Call("fail"); err == nil {
+			return 0, errors.New("expected trap")
+		}
+
+		if n == 0 {
+			return ptr, nil
+		}
+		v, err = c.Call("run", typesRuntime.ValueI32(n-1))
+		if err != nil {
+			return 0, fmt.Errorf("failed to call run: %w", err)
+		}
+		return ptr + int32(v[0].(typesRuntime.ValueI32)), nil
+	}
+
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithHostFunc("env", "host", host))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	// run(1) = 100 + 16 + run(0) = 100 + 16 + 100 + 24
+	got, err := r.Call("run", typesRuntime.ValueI32(1))
+	if err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.Value{typesRuntime.ValueI32(240)}; !slices.Equal(got, want) {
+		t.Errorf("unexpected return value: %v", got)
+	}
+
+	memory, err := r.Store().Memory(0)
+	if err != nil {
+		t.Errorf("failed to get memory: %v", err)
+		t.FailNow()
+	}
+	if memory.Data[16] != 1 || memory.Data[24] != 0 {
+		t.Errorf("unexpected memory: %v", memory.Data[16:32])
+	}
+
+	// the instance is still usable after the nested traps.
+	got, err = r.Call("run", typesRuntime.ValueI32(0))
+	if err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.Value{typesRuntime.ValueI32(132)}; !slices.Equal(got, want) {
+		t.Errorf("unexpected return value: %v", got)
+	}
+}
+
+func TestCallContext(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/spin.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = r.CallContext(ctx, "spin")
+	if !errors.Is(err, typesRuntime.ErrInterrupted) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	spinCall, err := runtime.ExportedFunc[func(context.Context) error](r, "spin_call")
+	if err != nil {
+		t.Errorf("failed to bind export: %v", err)
+		t.FailNow()
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := spinCall(ctx); !errors.Is(err, typesRuntime.ErrInterrupted) || !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// the instance is still usable after an interrupt.
+	got, err := r.Call("answer")
+	if err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.Value{typesRuntime.ValueI32(42)}; !slices.Equal(got, want) {
+		t.Errorf("unexpected return value: %v", got)
+	}
+}
+
+func TestCallContextHostFunc(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/typed.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	type key struct{}
+	scale := func(ctx context.Context, x int32, factor int64) (float64, error) {
+		v, ok := ctx.Value(key{}).(float64)
+		if !ok {
+			return 0, errors.New("missing context value")
+		}
+		return float64(x) * float64(factor) * v, nil
+	}
+
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithHostFunc("env", "scale", scale))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	ctx := context.WithValue(context.Background(), key{}, 0.5)
+	got, err := r.CallContext(ctx, "call_scale", typesRuntime.ValueI32(3), typesRuntime.ValueI64(5))
+	if err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.Value{typesRuntime.NewValueF64(7.5)}; !slices.Equal(got, want) {
+		t.Errorf("unexpected return value: %v", got)
+	}
+
+	if _, err := r.Call("call_scale", typesRuntime.ValueI32(3), typesRuntime.ValueI64(5)); err == nil {
+		t.Errorf("expected error without context value, got nil")
+	}
+}
+
+func TestFuel(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/fuel.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	c, err := runtime.Compile(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to compile module: %v", err)
+		t.FailNow()
+	}
+
+	const plenty = 1 << 20
+	r, err := c.Instantiate(runtime.WithFuel(plenty))
+	if err != nil {
+		t.Errorf("failed to instantiate module: %v", err)
+		t.FailNow()
+	}
+	if _, err := r.Call("sum", typesRuntime.ValueI32(100)); err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	consumed := plenty - r.Fuel()
+
+	// run the same call in small steps, refuelling and resuming each time it runs out.
+	r, err = c.Instantiate(runtime.WithFuel(10))
+	if err != nil {
+		t.Errorf("failed to instantiate module: %v", err)
+		t.FailNow()
+	}
+	total := uint64(10)
+	got, err := r.Call("sum", typesRuntime.ValueI32(100))
+	for errors.Is(err, typesRuntime.ErrOutOfFuel) {
+		r.AddFuel(10)
+		total += 10
+		got, err = r.Resume()
+	}
+	if err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.Value{typesRuntime.ValueI32(5050)}; !slices.Equal(got, want) {
+		t.Errorf("unexpected return value: %v", got)
+	}
+	if total-r.Fuel() != consumed {
+		t.Errorf("unexpected fuel consumption: %d, want %d", total-r.Fuel(), consumed)
+	}
+
+	if _, err := r.Resume(); err == nil {
+		t.Errorf("expected error without suspended call, got nil")
+	}
+
+	// a new call abandons the suspended one.
+	r.SetFuel(5)
+	if _, err := r.Call("sum", typesRuntime.ValueI32(100)); !errors.Is(err, typesRuntime.ErrOutOfFuel) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// the backtrace points at the instruction that ran out of fuel, which runs on resume.
+	r.SetFuel(0)
+	_, err = r.Call("sum", typesRuntime.ValueI32(100))
+	var trap *typesRuntime.Trap
+	if !errors.As(err, &trap) || !errors.Is(err, typesRuntime.ErrOutOfFuel) {
+		t.Errorf("expected out of fuel trap, got %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.TraceFrame{{FuncIndex: 0, Offset: 0}}; !slices.Equal(trap.Trace, want) {
+		t.Errorf("unexpected trace: %v", trap.Trace)
+	}
+	r.SetFuel(plenty)
+	got, err = r.Resume()
+	if err != nil {
+		t.Errorf("failed to resume: %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.Value{typesRuntime.ValueI32(5050)}; !slices.Equal(got, want) {
+		t.Errorf("unexpected return value: %v", got)
+	}
+
+	got, err = r.Call("sum", typesRuntime.ValueI32(3))
+	if err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.Value{typesRuntime.ValueI32(6)}; !slices.Equal(got, want) {
+		t.Errorf("unexpected return value: %v", got)
+	}
+}
+
+func TestFuelCosts(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/fuel.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithFuel(500), runtime.WithFuelCosts(map[opcode.Opcode]uint64{
+		opcode.OpcodeI32Add: 1000,
+	}))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	if _, err := r.Call("sum", typesRuntime.ValueI32(0)); err != nil {
+		t.Errorf("failed to call function: %v", err)
+	}
+	_, err = r.Call("sum", typesRuntime.ValueI32(1))
+	var trap *typesRuntime.Trap
+	if !errors.As(err, &trap) || !errors.Is(err, typesRuntime.ErrOutOfFuel) {
+		t.Errorf("expected out of fuel trap, got %v", err)
+		t.FailNow()
+	}
+	// i32.add is the eighth instruction of sum.
+	if want := []typesRuntime.TraceFrame{{FuncIndex: 0, Offset: 7}}; !slices.Equal(trap.Trace, want) {
+		t.Errorf("unexpected trace: %v", trap.Trace)
+	}
+
+	// instructions with the 0xFC prefix are priced by their sub-opcode.
+	b, err = os.ReadFile("../testdata/memory_bulk.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	const plenty = 1 << 20
+	r, err = runtime.New(bytes.NewReader(b), runtime.WithFuel(plenty),
+		runtime.WithFuelCosts(map[opcode.Opcode]uint64{opcode.OpcodeFCPrefix: 10}),
+		runtime.WithFCFuelCosts(map[opcode.OpcodeFC]uint64{opcode.OpcodeFCMemoryFill: 100}),
+	)
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	zero := []typesRuntime.Value{typesRuntime.ValueI32(0), typesRuntime.ValueI32(0), typesRuntime.ValueI32(0)}
+	consumed := make(map[string]uint64)
+	for _, name := range []string{"copy", "fill"} {
+		before := r.Fuel()
+		if _, err := r.Call(name, zero...); err != nil {
+			t.Errorf("%s: failed to call function: %v", name, err)
+			t.FailNow()
+		}
+		consumed[name] = before - r.Fuel()
+	}
+	if consumed["fill"]-consumed["copy"] != 90 {
+		t.Errorf("unexpected fuel consumption: copy %d, fill %d", consumed["copy"], consumed["fill"])
+	}
+}
+
+func TestTrap(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/trap.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithHostFunc("env", "nop", func() {}))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	tests := []struct {
+		name  string
+		args  []typesRuntime.Value
+		code  typesRuntime.TrapCode
+		err   error
+		trace []typesRuntime.TraceFrame
+	}{
+		{
+			name:  "div",
+			args:  []typesRuntime.Value{typesRuntime.ValueI32(1)},
+			code:  typesRuntime.TrapIntegerDivideByZero,
+			err:   typesRuntime.ErrIntegerDivideByZero,
+			trace: []typesRuntime.TraceFrame{{FuncIndex: 1, Offset: 2}, {FuncIndex: 2, Offset: 4}},
+		},
+		{
+			name:  "unreachable",
+			code:  typesRuntime.TrapUnreachable,
+			err:   typesRuntime.ErrUnreachable,
+			trace: []typesRuntime.TraceFrame{{FuncIndex: 3, Offset: 0}},
+		},
+		// the effective address does not wrap around at 32 bits.
+		{
+			name:  "load",
+			args:  []typesRuntime.Value{typesRuntime.ValueI32(1)},
+			code:  typesRuntime.TrapMemoryOutOfBounds,
+			err:   typesRuntime.ErrMemoryOutOfBounds,
+			trace: []typesRuntime.TraceFrame{{FuncIndex: 4, Offset: 1}},
+		},
+		{
+			name:  "store",
+			args:  []typesRuntime.Value{typesRuntime.ValueI32(1)},
+			code:  typesRuntime.TrapMemoryOutOfBounds,
+			err:   typesRuntime.ErrMemoryOutOfBounds,
+			trace: []typesRuntime.TraceFrame{{FuncIndex: 5, Offset: 2}},
+		},
+	}
+
+	for _, test := range tests {
+		_, err := r.Call(test.name, test.args...)
+
+		var trap *typesRuntime.Trap
+		if !errors.As(err, &trap) {
+			t.Errorf("%s: expected trap, got %v", test.name, err)
+			continue
+		}
+		if trap.Code != test.code {
+			t.Errorf("%s: unexpected trap code: %v", test.name, trap.Code)
+		}
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: unexpected cause: %v", test.name, err)
+		}
+		if !slices.Equal(trap.Trace, test.trace) {
+			t.Errorf("%s: unexpected trace: %v", test.name, trap.Trace)
+		}
+	}
+}
+
+func TestStackLimits(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/recurse.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	c, err := runtime.Compile(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to compile module: %v", err)
+		t.FailNow()
+	}
+
+	tests := []struct {
+		name string
+		opts []runtime.Option
+		ok   int32
+		over int32
+	}{
+		{name: "default", ok: 1000, over: math.MaxInt32},
+		{name: "call depth", opts: []runtime.Option{runtime.WithMaxCallDepth(100)}, ok: 99, over: 100},
+		// every level keeps one value on the stack during the nested call.
+		{name: "stack size", opts: []runtime.Option{runtime.WithMaxStackSize(50)}, ok: 49, over: 50},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := c.Instantiate(test.opts...)
+			if err != nil {
+				t.Errorf("failed to instantiate module: %v", err)
+				t.FailNow()
+			}
+
+			_, err = r.Call("depth", typesRuntime.ValueI32(test.over))
+			var trap *typesRuntime.Trap
+			if !errors.As(err, &trap) || trap.Code != typesRuntime.TrapStackExhausted {
+				t.Errorf("expected stack exhaustion, got %v", err)
+			}
+
+			// the instance is still usable after exhaustion.
+			got, err := r.Call("depth", typesRuntime.ValueI32(test.ok))
+			if err != nil {
+				t.Errorf("failed to call function: %v", err)
+				t.FailNow()
+			}
+			if want := []typesRuntime.Value{typesRuntime.ValueI32(test.ok)}; !slices.Equal(got, want) {
+				t.Errorf("unexpected return value: %v", got)
+			}
+		})
+	}
+}
+
+func TestPanicToTrap(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/import.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b), runtime.WithHostFunc("env", "add", func(x int32) int32 {
+		if x < 0 {
+			panic("negative")
+		}
+		return x + x
+	}))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	_, err = r.Call("call_add", typesRuntime.ValueI32(-1))
+	var trap *typesRuntime.Trap
+	if !errors.As(err, &trap) || trap.Code != typesRuntime.TrapInternal {
+		t.Errorf("expected internal trap, got %v", err)
+	}
+
+	// the instance is still usable after the panic.
+	got, err := r.Call("call_add", typesRuntime.ValueI32(2))
+	if err != nil {
+		t.Errorf("failed to call function: %v", err)
+		t.FailNow()
+	}
+	if want := []typesRuntime.Value{typesRuntime.ValueI32(4)}; !slices.Equal(got, want) {
+		t.Errorf("unexpected return value: %v", got)
+	}
+
+	if _, err := typesRuntime.NewValueF32(1).Bool(); !errors.Is(err, typesRuntime.ErrInvalidValue) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := (typesBinary.ExprValueConstF64{}).Int(); !errors.Is(err, typesRuntime.ErrInvalidValue) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPanicToTrapLinked(t *testing.T) {
+	t.Parallel()
+
+	compile := func(file string) *runtime.CompiledModule {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Errorf("failed to load testdata: %v", err)
+			t.FailNow()
+		}
+		c, err := runtime.Compile(bytes.NewReader(b))
+		if err != nil {
+			t.Errorf("failed to compile module: %v", err)
+			t.FailNow()
+		}
+		return c
+	}
+
+	linker := runtime.NewLinker()
+
+	a, err := linker.Instantiate(compile("../testdata/import.wasm"), runtime.WithHostFunc("env", "add", func(x int32) int32 {
+		if x < 0 {
+			panic("negative")
+		}
+		return x + x
+	}))
+	if err != nil {
+		t.Errorf("failed to instantiate a: %v", err)
+		t.FailNow()
+	}
+	linker.Register("a", a)
+
+	b, err := linker.Instantiate(compile("../testdata/linker_panic.wasm"))
+	if err != nil {
+		t.Errorf("failed to instantiate b: %v", err)
+		t.FailNow()
+	}
+
+	_, err = b.Call("call_add", typesRuntime.ValueI32(-1))
+	var trap *typesRuntime.Trap
+	if !errors.As(err, &trap) || trap.Code != typesRuntime.TrapInternal {
+		t.Errorf("expected internal trap, got %v", err)
+	}
+	if len(trap.Trace) != 2 {
+		t.Errorf("expected frames of both instances in the trace, got %v", trap.Trace)
+	}
+
+	// the panic unwinds the linked instance as well.
+	if a.StackLen() != 0 {
+		t.Errorf("unexpected values left on the stack of a: %d", a.StackLen())
+	}
+	if _, err := a.PopCallStack(); err == nil {
+		t.Errorf("unexpected frame left on the call stack of a")
+	}
+
+	for _, r := range []*runtime.Runtime{a, b} {
+		got, err := r.Call("call_add", typesRuntime.ValueI32(2))
+		if err != nil {
+			t.Errorf("failed to call function: %v", err)
+			t.FailNow()
+		}
+		if want := []typesRuntime.Value{typesRuntime.ValueI32(4)}; !slices.Equal(got, want) {
+			t.Errorf("unexpected return value: %v", got)
+		}
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/invalid.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	_, err = runtime.New(bytes.NewReader(b))
+	if !errors.Is(err, validation.ErrInvalid) {
+		t.Errorf("expected invalid module, got %v", err)
+		t.FailNow()
+	}
+
+	// i32.add is the third instruction of the second function.
+	var verr *validation.Error
+	if !errors.As(err, &verr) || verr.FuncIndex != 1 || verr.Offset != 2 {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestControlFlow(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/control_flow.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	r, err := runtime.New(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to create runtime: %v", err)
+		t.FailNow()
+	}
+
+	i32 := func(v ...int32) []typesRuntime.Value {
+		values := make([]typesRuntime.Value, 0, len(v))
+		for _, v := range v {
+			values = append(values, typesRuntime.ValueI32(v))
+		}
+		return values
+	}
+
+	tests := []struct {
+		name string
+		args []typesRuntime.Value
+		want []typesRuntime.Value
+	}{
+		{"nested_if", i32(0, 0), i32(10)},
+		{"nested_if", i32(0, 1), i32(10)},
+		{"nested_if", i32(1, 0), i32(2)},
+		{"nested_if", i32(1, 1), i32(1)},
+		{"br_value", i32(0), i32(3)},
+		{"br_value", i32(1), i32(3)},
+		{"br_table", i32(0), i32(10)},
+		{"br_table", i32(1), i32(11)},
+		{"br_table", i32(2), i32(12)},
+		{"br_table", i32(-1), i32(12)},
+		{"countdown", i32(0), i32(0)},
+		{"countdown", i32(100), i32(5050)},
+		{"swap", i32(1, 2), i32(2, 1)},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s%v", test.name, test.args), func(t *testing.T) {
+			got, err := r.Call(test.name, test.args...)
+			if err != nil {
+				t.Errorf("failed to call function: %v", err)
+				t.FailNow()
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("unexpected return value: %v, want %v", got, test.want)
+			}
+		})
+	}
+}
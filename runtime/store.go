@@ -1,30 +1,60 @@
 package runtime
 
 import (
+	"errors"
 	"fmt"
 	"iter"
 
 	"github.com/Warashi/wasmium/binary"
 	tbinary "github.com/Warashi/wasmium/types/binary"
-	"github.com/Warashi/wasmium/types/instruction"
 	"github.com/Warashi/wasmium/types/runtime"
 )
 
-const PageSize = 65536 // 64 Ki
+const PageSize = runtime.PageSize
 
 type Store struct {
+	types    []tbinary.FuncType
 	funcs    []runtime.FuncInst
 	module   runtime.ModuleInst
-	memories []runtime.MemoryInst
-	globals  []runtime.GlobalInst
+	tables   []*runtime.TableInst
+	memories []*runtime.MemoryInst
+	globals  []*runtime.GlobalInst
+	elements []*runtime.ElementInst
+	datas    []*runtime.DataInst
 }
 
-func NewStore(module *binary.Module) (*Store, error) {
-	var funcs []runtime.FuncInst
+func NewStore(module *binary.Module, opts ...Option) (*Store, error) {
+	c, err := compile(module)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newStore(nil, newConfig(opts...))
+}
+
+// newStore allocates the entities of an instance. Functions defined by the module belong to owner.
+func (c *CompiledModule) newStore(owner runtime.Runtime, cfg config) (*Store, error) {
+	module := c.module
+
+	var (
+		funcs    []runtime.FuncInst
+		tables   []*runtime.TableInst
+		memories []*runtime.MemoryInst
+		globals  []*runtime.GlobalInst
+	)
+	if len(cfg.errs) > 0 {
+		return nil, fmt.Errorf("invalid option: %w", errors.Join(cfg.errs...))
+	}
 
+	// NOTE: imported entities come first in each index space.
+	// Every import is resolved before reporting, so that all failures are listed at once.
+	var importErrs []error
 	for _, impt := range module.ImportSection() {
 		moduleName := impt.Module
 		field := impt.Field
+		name := importName{moduleName, field}
+		unknown := fmt.Errorf("%w: %s.%s", runtime.ErrUnknownImport, moduleName, field)
+		incompatible := fmt.Errorf("%w: %s.%s", runtime.ErrIncompatibleImportType, moduleName, field)
 		switch desc := impt.Desc.(type) {
 		case tbinary.ImportDescFunc:
 			if desc.Index < 0 || len(module.TypeSection()) <= int(desc.Index) {
@@ -32,44 +62,75 @@ func NewStore(module *binary.Module) (*Store, error) {
 			}
 			funcType := module.TypeSection()[desc.Index]
 
+			if f, ok := cfg.funcs[name]; ok {
+				if !funcTypeOf(f).Equal(funcType) {
+					importErrs = append(importErrs, incompatible)
+					continue
+				}
+				funcs = append(funcs, f)
+				continue
+			}
+
+			host, ok := cfg.imports.lookup(moduleName, field)
+			if !ok {
+				importErrs = append(importErrs, unknown)
+				continue
+			}
+			if !host.FuncType.Equal(funcType) {
+				importErrs = append(importErrs, incompatible)
+				continue
+			}
+
 			funcs = append(funcs, runtime.ExternalFuncInst{
 				Module:   moduleName,
 				Func:     field,
 				FuncType: funcType,
+				Instance: owner,
 			})
-		}
-	}
-
-	for body, typeIdx := range zipSlice(module.CodeSection(), module.FunctionSection()) {
-		funcType := module.TypeSection()[typeIdx]
-
-		localslen := 0
-
-		for _, local := range body.Locals {
-			localslen += int(local.TypeCount)
-		}
-		locals := make([]tbinary.ValueType, 0, localslen)
-
-		for _, local := range body.Locals {
-			for range local.TypeCount {
-				locals = append(locals, local.ValueType)
+		case tbinary.ImportDescTable:
+			table, ok := cfg.tables[name]
+			if !ok {
+				importErrs = append(importErrs, unknown)
+				continue
 			}
+			if table.Type != desc.Type.ElementType || !matchLimits(table.Size(), table.Max, table.HasMax, desc.Type.Limits) {
+				importErrs = append(importErrs, incompatible)
+				continue
+			}
+			tables = append(tables, table)
+		case tbinary.ImportDescMemory:
+			memory, ok := cfg.memories[name]
+			if !ok {
+				importErrs = append(importErrs, unknown)
+				continue
+			}
+			if !matchLimits(memory.Size(), memory.Max, memory.HasMax, desc.Memory.Limits) {
+				importErrs = append(importErrs, incompatible)
+				continue
+			}
+			memories = append(memories, memory)
+		case tbinary.ImportDescGlobal:
+			global, ok := cfg.globals[name]
+			if !ok {
+				importErrs = append(importErrs, unknown)
+				continue
+			}
+			if global.Value.Type() != runtime.ValueType(desc.Type.ValueType) || global.Mutable != desc.Type.Mutable {
+				importErrs = append(importErrs, incompatible)
+				continue
+			}
+			globals = append(globals, global)
+		default:
+			return nil, fmt.Errorf("unsupported import description: %T", desc)
 		}
+	}
+	if len(importErrs) > 0 {
+		return nil, fmt.Errorf("failed to resolve imports: %w", errors.Join(importErrs...))
+	}
 
-		insts, err := instruction.Convert(body.Code)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert instructions: %w", err)
-		}
-
-		funcInst := runtime.InternalFuncInst{
-			FuncType: funcType,
-			Code: runtime.Func{
-				Locals: locals,
-				Body:   insts,
-			},
-		}
-
-		funcs = append(funcs, funcInst)
+	for _, code := range c.codes {
+		code.Instance = owner
+		funcs = append(funcs, code)
 	}
 
 	exports := make(map[string]runtime.ExportInst, len(module.ExportSection()))
@@ -80,16 +141,22 @@ func NewStore(module *binary.Module) (*Store, error) {
 		}
 	}
 
-	memories := make([]runtime.MemoryInst, 0, len(module.MemorySection()))
+	for _, table := range module.TableSection() {
+		if table.Limits.Min > runtime.MaxTableSize {
+			return nil, fmt.Errorf("table size exceeds limit: %d > %d", table.Limits.Min, runtime.MaxTableSize)
+		}
+		tables = append(tables, runtime.NewTableInst(table))
+	}
+
 	for _, memory := range module.MemorySection() {
-		mem := runtime.MemoryInst{
-			Data: make([]byte, memory.Limits.Min*PageSize),
-			Max:  memory.Limits.Max,
+		if cfg.memoryLimitPages > 0 && memory.Limits.Min > cfg.memoryLimitPages {
+			return nil, fmt.Errorf("memory size exceeds limit: %d > %d pages", memory.Limits.Min, cfg.memoryLimitPages)
 		}
+		mem := runtime.NewMemoryInst(memory.Limits)
+		mem.Limit = cfg.memoryLimitPages
 		memories = append(memories, mem)
 	}
 
-	globals := make([]runtime.GlobalInst, 0, len(module.GlobalSection()))
 	for _, global := range module.GlobalSection() {
 		var v runtime.Value
 		switch expr := global.InitExpr.(type) {
@@ -101,46 +168,155 @@ func NewStore(module *binary.Module) (*Store, error) {
 			v = runtime.ValueF32(expr)
 		case tbinary.ExprValueConstF64:
 			v = runtime.ValueF64(expr)
+		case tbinary.ExprRefNull:
+			v = runtime.NullRef(tbinary.RefType(expr))
+		case tbinary.ExprRefFunc:
+			if len(funcs) <= int(expr) {
+				return nil, fmt.Errorf("invalid function index: %d", expr)
+			}
+			v = runtime.ValueFuncRef{Func: funcs[expr]}
+		case tbinary.ExprGlobalIndex:
+			if len(globals) <= int(expr) {
+				return nil, fmt.Errorf("invalid global index: %d", expr)
+			}
+			v = globals[expr].Value
 		default:
 			return nil, fmt.Errorf("unsupported global type: %T", expr)
 		}
 
-		globals = append(globals, runtime.GlobalInst{
+		globals = append(globals, &runtime.GlobalInst{
 			Value:   v,
 			Mutable: global.Type.Mutable,
 		})
 	}
 
-	eval := func(expr tbinary.Expr) (int, error) {
+	// NOTE: segment offsets are i32 values interpreted as unsigned, so they are not sign-extended.
+	evalOffset := func(expr tbinary.Expr) (uint32, error) {
+		var v runtime.Value
 		switch expr := expr.(type) {
-		case tbinary.ExprValue:
-			return expr.Int(), nil
+		case tbinary.ExprValueConstI32:
+			return uint32(expr), nil
 		case tbinary.ExprGlobalIndex:
-			if expr < 0 || len(globals) <= int(expr) {
+			if len(globals) <= int(expr) {
 				return 0, fmt.Errorf("invalid global index: %d", expr)
 			}
-			return globals[expr].Value.Int(), nil
+			v = globals[expr].Value
 		default:
-			return 0, fmt.Errorf("unsupported global type: %T", expr)
+			return 0, fmt.Errorf("unsupported offset expression: %T", expr)
+		}
+		i, ok := v.(runtime.ValueI32)
+		if !ok {
+			return 0, fmt.Errorf("offset of %T: %w", v, runtime.ErrInvalidValue)
 		}
+		return uint32(i), nil
 	}
 
+	evalRef := func(expr tbinary.Expr) (runtime.Ref, error) {
+		switch expr := expr.(type) {
+		case tbinary.ExprRefNull:
+			return runtime.NullRef(tbinary.RefType(expr)), nil
+		case tbinary.ExprRefFunc:
+			if len(funcs) <= int(expr) {
+				return nil, fmt.Errorf("invalid function index: %d", expr)
+			}
+			return runtime.ValueFuncRef{Func: funcs[expr]}, nil
+		case tbinary.ExprGlobalIndex:
+			if len(globals) <= int(expr) {
+				return nil, fmt.Errorf("invalid global index: %d", expr)
+			}
+			ref, ok := globals[expr].Value.(runtime.Ref)
+			if !ok {
+				return nil, fmt.Errorf("global %d is not a reference: %T", expr, globals[expr].Value)
+			}
+			return ref, nil
+		default:
+			return nil, fmt.Errorf("unsupported element expression: %T", expr)
+		}
+	}
+
+	// NOTE: all segments are checked before any of them is applied,
+	// so that a failed instantiation never leaves tables or memories partially initialized.
+	var inits []func()
+
+	elements := make([]*runtime.ElementInst, 0, len(module.ElementSection()))
+	for _, elem := range module.ElementSection() {
+		refs := make([]runtime.Ref, 0, len(elem.Init))
+		for _, init := range elem.Init {
+			ref, err := evalRef(init)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate element: %w", err)
+			}
+			refs = append(refs, ref)
+		}
+
+		if elem.Mode == tbinary.ElementModePassive {
+			elements = append(elements, &runtime.ElementInst{Type: elem.Type, Elements: refs})
+			continue
+		}
+
+		// active and declarative segments are dropped during instantiation.
+		elements = append(elements, &runtime.ElementInst{Type: elem.Type})
+
+		if elem.Mode != tbinary.ElementModeActive {
+			continue
+		}
+		if len(tables) <= int(elem.TableIndex) {
+			return nil, fmt.Errorf("invalid table index: %d", elem.TableIndex)
+		}
+		table := tables[elem.TableIndex]
+		offset, err := evalOffset(elem.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate offset: %w", err)
+		}
+		if uint64(offset)+uint64(len(refs)) > uint64(len(table.Elements)) {
+			return nil, fmt.Errorf("element segment at offset %d does not fit in table", offset)
+		}
+		inits = append(inits, func() { copy(table.Elements[offset:], refs) })
+	}
+
+	datas := make([]*runtime.DataInst, 0, len(module.DataSection()))
 	for _, data := range module.DataSection() {
+		if data.Mode != tbinary.DataModeActive {
+			datas = append(datas, &runtime.DataInst{Data: data.Init})
+			continue
+		}
+		if len(memories) <= int(data.MemoryIndex) {
+			return nil, fmt.Errorf("invalid memory index: %d", data.MemoryIndex)
+		}
 		memory := memories[data.MemoryIndex]
-		offset, err := eval(data.Offset)
+		offset, err := evalOffset(data.Offset)
 		if err != nil {
 			return nil, fmt.Errorf("failed to evaluate offset: %w", err)
 		}
-		if offset+len(data.Init) > len(memory.Data) {
-			return nil, fmt.Errorf("data segment does not fit in memory")
+		if uint64(offset)+uint64(len(data.Init)) > uint64(len(memory.Data)) {
+			return nil, fmt.Errorf("data segment at offset %d does not fit in memory", offset)
+		}
+		inits = append(inits, func() { copy(memory.Data[offset:], data.Init) })
+		// active segments are dropped once they are copied into memory.
+		datas = append(datas, &runtime.DataInst{})
+	}
+
+	if start, ok := module.Start(); ok {
+		if len(funcs) <= int(start) {
+			return nil, fmt.Errorf("invalid start function index: %d", start)
+		}
+		if funcType := funcTypeOf(funcs[start]); len(funcType.Params) != 0 || len(funcType.Results) != 0 {
+			return nil, fmt.Errorf("invalid start function type: %v", funcType)
 		}
-		copy(memory.Data[offset:], data.Init)
+	}
+
+	for _, init := range inits {
+		init()
 	}
 
 	return &Store{
+		types:    module.TypeSection(),
 		funcs:    funcs,
+		tables:   tables,
 		memories: memories,
 		globals:  globals,
+		elements: elements,
+		datas:    datas,
 		module: runtime.ModuleInst{
 			Exports: exports,
 		},
@@ -155,13 +331,35 @@ func (s *Store) Module() runtime.ModuleInst {
 	return s.module
 }
 
-func (s *Store) Memory(n int) (runtime.MemoryInst, error) {
+func (s *Store) Memory(n int) (*runtime.MemoryInst, error) {
 	if n < 0 || len(s.memories) <= n {
-		return runtime.MemoryInst{}, fmt.Errorf("invalid memory index: %d", n)
+		return nil, fmt.Errorf("invalid memory index: %d", n)
 	}
 	return s.memories[n], nil
 }
 
+// matchLimits reports whether an imported entity with the given size and maximum satisfies the limits.
+func matchLimits(size, max uint32, hasMax bool, limits tbinary.Limits) bool {
+	if size < limits.Min {
+		return false
+	}
+	if !limits.HasMax {
+		return true
+	}
+	return hasMax && max <= limits.Max
+}
+
+func funcTypeOf(f runtime.FuncInst) tbinary.FuncType {
+	switch f := f.(type) {
+	case runtime.InternalFuncInst:
+		return f.FuncType
+	case runtime.ExternalFuncInst:
+		return f.FuncType
+	default:
+		return tbinary.FuncType{}
+	}
+}
+
 func zipSlice[A, B any, SA ~[]A, SB ~[]B](a SA, b SB) iter.Seq2[A, B] {
 	return func(yield func(A, B) bool) {
 		for i := range min(len(a), len(b)) {
@@ -3,9 +3,11 @@ package runtime
 import (
 	"bytes"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/Warashi/wasmium/binary"
+	"github.com/Warashi/wasmium/types/runtime"
 )
 
 func TestInitMemory(t *testing.T) {
@@ -45,3 +47,65 @@ func TestInitMemory(t *testing.T) {
 		t.Errorf("unexpected memory content: %s", string(store.memories[0].Data[5:11]))
 	}
 }
+
+func TestInitTable(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../testdata/table.wasm")
+	if err != nil {
+		t.Errorf("failed to load testdata: %v", err)
+		t.FailNow()
+	}
+
+	module, err := binary.NewModule(bytes.NewReader(b))
+	if err != nil {
+		t.Errorf("failed to parse wasm: %v", err)
+		t.FailNow()
+	}
+
+	store, err := NewStore(module)
+	if err != nil {
+		t.Errorf("failed to create store: %v", err)
+		t.FailNow()
+	}
+
+	if len(store.tables) != 2 {
+		t.Errorf("unexpected number of tables: %d", len(store.tables))
+		t.FailNow()
+	}
+
+	tests := []struct {
+		table, index int
+		want         int // index into store.funcs, or -1 for null
+	}{
+		{0, 0, -1},
+		{0, 1, 0},
+		{0, 2, 1},
+		{0, 3, 2},
+		{0, 4, -1},
+		{1, 0, 2},
+		{1, 1, -1},
+	}
+
+	for _, test := range tests {
+		elements := store.tables[test.table].Elements
+		if len(elements) <= test.index {
+			t.Errorf("table %d is too small: %d", test.table, len(elements))
+			continue
+		}
+		got, ok := elements[test.index].(runtime.ValueFuncRef)
+		if !ok {
+			t.Errorf("table %d[%d]: expected funcref, got %#v", test.table, test.index, elements[test.index])
+			continue
+		}
+		if test.want < 0 {
+			if !got.IsNull() {
+				t.Errorf("table %d[%d]: expected null, got %#v", test.table, test.index, got)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got.Func, store.funcs[test.want]) {
+			t.Errorf("table %d[%d]: expected func %d, got %#v", test.table, test.index, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	tbinary "github.com/Warashi/wasmium/types/binary"
+	"github.com/Warashi/wasmium/types/runtime"
+)
+
+var (
+	callerType  = reflect.TypeFor[*Caller]()
+	contextType = reflect.TypeFor[context.Context]()
+	errorType   = reflect.TypeFor[error]()
+)
+
+// signature is the wasm function type of a Go function.
+// A leading *Caller or context.Context parameter and a trailing error result are not part of the wasm type.
+type signature struct {
+	funcType   tbinary.FuncType
+	hasCaller  bool
+	hasContext bool
+	hasError   bool
+}
+
+func signatureOf(t reflect.Type) (signature, error) {
+	if t.Kind() != reflect.Func {
+		return signature{}, fmt.Errorf("not a function: %v", t)
+	}
+	if t.IsVariadic() {
+		return signature{}, fmt.Errorf("variadic function is not supported: %v", t)
+	}
+
+	var sig signature
+
+	params := make([]reflect.Type, 0, t.NumIn())
+	for i := range t.NumIn() {
+		params = append(params, t.In(i))
+	}
+	if len(params) > 0 {
+		switch params[0] {
+		case callerType:
+			sig.hasCaller = true
+			params = params[1:]
+		case contextType:
+			sig.hasContext = true
+			params = params[1:]
+		}
+	}
+
+	results := make([]reflect.Type, 0, t.NumOut())
+	for i := range t.NumOut() {
+		results = append(results, t.Out(i))
+	}
+	if len(results) > 0 && results[len(results)-1] == errorType {
+		sig.hasError = true
+		results = results[:len(results)-1]
+	}
+
+	for _, param := range params {
+		v, ok := valueTypeOf(param)
+		if !ok {
+			return signature{}, fmt.Errorf("unsupported parameter type: %v", param)
+		}
+		sig.funcType.Params = append(sig.funcType.Params, v)
+	}
+	for _, result := range results {
+		v, ok := valueTypeOf(result)
+		if !ok {
+			return signature{}, fmt.Errorf("unsupported result type: %v", result)
+		}
+		sig.funcType.Results = append(sig.funcType.Results, v)
+	}
+
+	return sig, nil
+}
+
+// valueTypeOf returns the wasm value type representing Go values of type t.
+func valueTypeOf(t reflect.Type) (tbinary.ValueType, bool) {
+	switch t.Kind() {
+	case reflect.Int32, reflect.Uint32, reflect.Bool:
+		return tbinary.ValueTypeI32, true
+	case reflect.Int64, reflect.Uint64:
+		return tbinary.ValueTypeI64, true
+	case reflect.Float32:
+		return tbinary.ValueTypeF32, true
+	case reflect.Float64:
+		return tbinary.ValueTypeF64, true
+	default:
+		return 0, false
+	}
+}
+
+// goValueOf converts a wasm value to a Go value of type t.
+func goValueOf(v runtime.Value, t reflect.Type) (reflect.Value, error) {
+	var (
+		x   any
+		err error
+	)
+	switch t.Kind() {
+	case reflect.Int32:
+		x, err = runtime.ValueTo[int32](v)
+	case reflect.Uint32:
+		x, err = runtime.ValueTo[uint32](v)
+	case reflect.Int64:
+		x, err = runtime.ValueTo[int64](v)
+	case reflect.Uint64:
+		x, err = runtime.ValueTo[uint64](v)
+	case reflect.Float32:
+		x, err = runtime.ValueTo[float32](v)
+	case reflect.Float64:
+		x, err = runtime.ValueTo[float64](v)
+	case reflect.Bool:
+		x, err = runtime.ValueTo[bool](v)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type: %v", t)
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	// NOTE: Convert supports named types such as `type fd int32`.
+	return reflect.ValueOf(x).Convert(t), nil
+}
+
+// valueOf converts a Go value to a wasm value.
+func valueOf(v reflect.Value) (runtime.Value, error) {
+	switch v.Kind() {
+	case reflect.Int32:
+		return runtime.ValueFrom(int32(v.Int()))
+	case reflect.Uint32:
+		return runtime.ValueFrom(uint32(v.Uint()))
+	case reflect.Int64:
+		return runtime.ValueFrom(v.Int())
+	case reflect.Uint64:
+		return runtime.ValueFrom(v.Uint())
+	case reflect.Float32:
+		return runtime.ValueFrom(float32(v.Float()))
+	case reflect.Float64:
+		return runtime.ValueFrom(v.Float())
+	case reflect.Bool:
+		return runtime.ValueFrom(v.Bool())
+	default:
+		return nil, fmt.Errorf("unsupported type: %v", v.Type())
+	}
+}
+
+// NewHostFunc adapts a Go function such as `func(context.Context, int32, int64) (float64, error)`
+// to a host function, inferring its wasm type from the Go signature.
+// Parameters and results may be int32, uint32, int64, uint64, float32, float64 or bool.
+// A leading *Caller or context.Context parameter and a trailing error result are optional.
+func NewHostFunc(fn any) (HostFunc, error) {
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() {
+		return HostFunc{}, fmt.Errorf("nil function")
+	}
+	t := v.Type()
+
+	sig, err := signatureOf(t)
+	if err != nil {
+		return HostFunc{}, err
+	}
+
+	call := func(c *Caller, args ...runtime.Value) ([]runtime.Value, error) {
+		in := make([]reflect.Value, 0, t.NumIn())
+		switch {
+		case sig.hasCaller:
+			in = append(in, reflect.ValueOf(c))
+		case sig.hasContext:
+			in = append(in, reflect.ValueOf(c.Context()))
+		}
+		for i, arg := range args {
+			x, err := goValueOf(arg, t.In(len(in)))
+			if err != nil {
+				return nil, fmt.Errorf("invalid argument %d: %w", i, err)
+			}
+			in = append(in, x)
+		}
+
+		out := v.Call(in)
+		if sig.hasError {
+			if err, _ := out[len(out)-1].Interface().(error); err != nil {
+				return nil, err
+			}
+			out = out[:len(out)-1]
+		}
+
+		results := make([]runtime.Value, 0, len(out))
+		for _, o := range out {
+			result, err := valueOf(o)
+			if err != nil {
+				return nil, fmt.Errorf("invalid result: %w", err)
+			}
+			results = append(results, result)
+		}
+
+		return results, nil
+	}
+
+	return HostFunc{FuncType: sig.funcType, Func: call}, nil
+}
+
+// ExportedFunc returns a typed Go function calling the exported function name of r.
+// F must be a function type such as `func(int32, int64) (float64, error)` whose wasm type matches the export.
+// The trailing error result is required and reports traps.
+// With a leading context.Context parameter, the function is called with CallContext.
+func ExportedFunc[F any](r *Runtime, name string) (F, error) {
+	var fn F
+	t := reflect.TypeOf(&fn).Elem()
+
+	sig, err := signatureOf(t)
+	if err != nil {
+		return fn, err
+	}
+	if sig.hasCaller {
+		return fn, fmt.Errorf("caller parameter is not supported: %v", t)
+	}
+	if !sig.hasError {
+		return fn, fmt.Errorf("function must return an error: %v", t)
+	}
+
+	export, ok := r.store.module.Exported(name)
+	if !ok {
+		return fn, fmt.Errorf("export not found: %s", name)
+	}
+	desc, ok := export.Desc.(tbinary.ExportDescFunc)
+	if !ok || len(r.store.funcs) <= int(desc.Index) {
+		return fn, fmt.Errorf("export is not a function: %s", name)
+	}
+	if funcType := funcTypeOf(r.store.funcs[desc.Index]); !funcType.Equal(sig.funcType) {
+		return fn, fmt.Errorf("incompatible function type: %s is %v, not %v", name, funcType, sig.funcType)
+	}
+
+	call := func(in []reflect.Value) []reflect.Value {
+		out := make([]reflect.Value, t.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(t.Out(i))
+		}
+		fail := func(err error) []reflect.Value {
+			out[len(out)-1] = reflect.ValueOf(&err).Elem()
+			return out
+		}
+
+		ctx := context.Background()
+		if sig.hasContext {
+			if c, ok := in[0].Interface().(context.Context); ok {
+				ctx = c
+			}
+			in = in[1:]
+		}
+
+		args := make([]runtime.Value, 0, len(in))
+		for _, x := range in {
+			arg, err := valueOf(x)
+			if err != nil {
+				return fail(fmt.Errorf("invalid argument: %w", err))
+			}
+			args = append(args, arg)
+		}
+
+		results, err := r.CallContext(ctx, name, args...)
+		if err != nil {
+			return fail(err)
+		}
+
+		for i, result := range results {
+			x, err := goValueOf(result, t.Out(i))
+			if err != nil {
+				return fail(fmt.Errorf("invalid result %d: %w", i, err))
+			}
+			out[i] = x
+		}
+
+		return out
+	}
+
+	reflect.ValueOf(&fn).Elem().Set(reflect.MakeFunc(t, call))
+
+	return fn, nil
+}
@@ -10,10 +10,15 @@ func (s *Stack[T]) Push(v T) {
 	*s = append(*s, v)
 }
 
-func (s *Stack[T]) Pop() T {
+// Pop removes and returns the top value. It reports false if the stack is empty.
+func (s *Stack[T]) Pop() (T, bool) {
+	if len(*s) == 0 {
+		var zero T
+		return zero, false
+	}
 	r := (*s)[len(*s)-1]
 	*s = (*s)[:len(*s)-1]
-	return r
+	return r, true
 }
 
 func (s *Stack[T]) Drain(n int) {
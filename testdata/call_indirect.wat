@@ -0,0 +1,16 @@
+(module
+  (type $i32_i32 (func (param i32) (result i32)))
+  (type $void_i32 (func (result i32)))
+  (import "env" "double" (func $double (type $i32_i32)))
+  (table 4 funcref)
+  (elem (i32.const 0) $inc $double $answer)
+  (func $inc (type $i32_i32)
+    local.get 0
+    i32.const 1
+    i32.add)
+  (func $answer (type $void_i32)
+    i32.const 42)
+  (func (export "call") (param i32 i32) (result i32)
+    local.get 0
+    local.get 1
+    call_indirect (type $i32_i32)))
@@ -0,0 +1,8 @@
+(module
+  (import "env" "peek" (func $peek (param i32) (result i32)))
+  (memory (export "mem") 1)
+  (global (export "base") i32 (i32.const 7))
+  (data (i32.const 8) "\2a")
+  (func (export "run") (param i32) (result i32)
+    local.get 0
+    call $peek))
@@ -0,0 +1,80 @@
+(module
+  ;; an if without else that contains an if with else.
+  (func (export "nested_if") (param i32 i32) (result i32)
+    (local i32)
+    i32.const 10
+    local.set 2
+    local.get 0
+    if
+      local.get 1
+      if
+        i32.const 1
+        local.set 2
+      else
+        i32.const 2
+        local.set 2
+      end
+    end
+    local.get 2)
+
+  ;; br carries a value out of nested blocks and discards the operands above it.
+  (func (export "br_value") (param i32) (result i32)
+    block (result i32)
+      i32.const 1
+      block (result i32)
+        i32.const 2
+        i32.const 3
+        local.get 0
+        br_if 1
+        drop
+      end
+      i32.add
+    end)
+
+  ;; br_table selects one of the enclosing blocks, and the last one by default.
+  (func (export "br_table") (param i32) (result i32)
+    block
+      block
+        block
+          local.get 0
+          br_table 0 1 2
+        end
+        i32.const 10
+        return
+      end
+      i32.const 11
+      return
+    end
+    i32.const 12)
+
+  ;; countdown sums n + ... + 1 in a loop.
+  (func (export "countdown") (param i32) (result i32)
+    (local i32)
+    block
+      loop
+        local.get 0
+        i32.eqz
+        br_if 1
+        local.get 1
+        local.get 0
+        i32.add
+        local.set 1
+        local.get 0
+        i32.const 1
+        i32.sub
+        local.set 0
+        br 0
+      end
+    end
+    local.get 1)
+
+  ;; a block with parameters and multiple results.
+  (func (export "swap") (param i32 i32) (result i32 i32)
+    local.get 0
+    local.get 1
+    block (param i32 i32) (result i32 i32)
+      local.set 0
+      local.set 1
+      local.get 0
+      local.get 1
+    end))
@@ -0,0 +1,12 @@
+(module
+  (memory 1)
+  (global $count (mut i32) (i32.const 0))
+  (func (export "incr") (result i32)
+    global.get $count
+    i32.const 1
+    i32.add
+    global.set $count
+    i32.const 0
+    global.get $count
+    i32.store
+    global.get $count))
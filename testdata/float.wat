@@ -0,0 +1,42 @@
+(module
+  (func (export "f32.add") (param f32 f32) (result f32)
+    local.get 0
+    local.get 1
+    f32.add)
+  (func (export "f32.min") (param f32 f32) (result f32)
+    local.get 0
+    local.get 1
+    f32.min)
+  (func (export "f32.neg") (param f32) (result f32)
+    local.get 0
+    f32.neg)
+  (func (export "f32.nearest") (param f32) (result f32)
+    local.get 0
+    f32.nearest)
+  (func (export "f32.eq") (param f32 f32) (result i32)
+    local.get 0
+    local.get 1
+    f32.eq)
+  (func (export "f64.max") (param f64 f64) (result f64)
+    local.get 0
+    local.get 1
+    f64.max)
+  (func (export "f64.abs") (param f64) (result f64)
+    local.get 0
+    f64.abs)
+  (func (export "f64.copysign") (param f64 f64) (result f64)
+    local.get 0
+    local.get 1
+    f64.copysign)
+  (func (export "f64.sqrt") (param f64) (result f64)
+    local.get 0
+    f64.sqrt)
+  (func (export "f64.convert_i64_u") (param i64) (result f64)
+    local.get 0
+    f64.convert_i64_u)
+  (func (export "f32.demote_f64") (param f64) (result f32)
+    local.get 0
+    f32.demote_f64)
+  (func (export "i32.reinterpret_f32") (param f32) (result i32)
+    local.get 0
+    i32.reinterpret_f32))
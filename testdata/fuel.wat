@@ -0,0 +1,18 @@
+(module
+  (func (export "sum") (param i32) (result i32)
+    (local i32)
+    (block $done
+      (loop $l
+        local.get 0
+        i32.eqz
+        br_if $done
+        local.get 1
+        local.get 0
+        i32.add
+        local.set 1
+        local.get 0
+        i32.const 1
+        i32.sub
+        local.set 0
+        br $l))
+    local.get 1))
@@ -2,7 +2,7 @@
   (import "wasi_snapshot_preview1" "fd_write"
     (func $fd_write (param i32 i32 i32 i32) (result i32)))
 
-  (memory 1)
+  (memory (export "memory") 1)
 
   (data (i32.const 0) "Hello, World!\n")
 
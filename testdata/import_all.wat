@@ -0,0 +1,24 @@
+(module
+  (import "env" "memory" (memory 1))
+  (import "env" "table" (table 2 funcref))
+  (import "env" "counter" (global $counter (mut i32)))
+  (import "env" "base" (global $base i32))
+  (global $own i32 (i32.const 100))
+  (table $own 1 externref)
+  (func (export "store") (param i32 i32)
+    local.get 0
+    local.get 1
+    i32.store)
+  (func (export "incr")
+    global.get $counter
+    i32.const 1
+    i32.add
+    global.set $counter)
+  (func (export "sum") (result i32)
+    global.get $base
+    global.get $own
+    i32.add)
+  (func (export "table_size") (result i32)
+    table.size 0)
+  (func (export "own_table_size") (result i32)
+    table.size $own))
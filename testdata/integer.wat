@@ -0,0 +1,61 @@
+(module
+  (func (export "i32.div_s") (param i32 i32) (result i32)
+    local.get 0
+    local.get 1
+    i32.div_s)
+  (func (export "i32.div_u") (param i32 i32) (result i32)
+    local.get 0
+    local.get 1
+    i32.div_u)
+  (func (export "i32.rem_s") (param i32 i32) (result i32)
+    local.get 0
+    local.get 1
+    i32.rem_s)
+  (func (export "i32.rem_u") (param i32 i32) (result i32)
+    local.get 0
+    local.get 1
+    i32.rem_u)
+  (func (export "i32.shr_u") (param i32 i32) (result i32)
+    local.get 0
+    local.get 1
+    i32.shr_u)
+  (func (export "i32.rotl") (param i32 i32) (result i32)
+    local.get 0
+    local.get 1
+    i32.rotl)
+  (func (export "i32.clz") (param i32) (result i32)
+    local.get 0
+    i32.clz)
+  (func (export "i32.ge_u") (param i32 i32) (result i32)
+    local.get 0
+    local.get 1
+    i32.ge_u)
+  (func (export "i64.div_s") (param i64 i64) (result i64)
+    local.get 0
+    local.get 1
+    i64.div_s)
+  (func (export "i64.rem_s") (param i64 i64) (result i64)
+    local.get 0
+    local.get 1
+    i64.rem_s)
+  (func (export "i64.extend_i32_u") (param i32) (result i64)
+    local.get 0
+    i64.extend_i32_u)
+  (func (export "i32.wrap_i64") (param i64) (result i32)
+    local.get 0
+    i32.wrap_i64)
+  (func (export "i32.extend8_s") (param i32) (result i32)
+    local.get 0
+    i32.extend8_s)
+  (func (export "i32.extend16_s") (param i32) (result i32)
+    local.get 0
+    i32.extend16_s)
+  (func (export "i64.extend8_s") (param i64) (result i64)
+    local.get 0
+    i64.extend8_s)
+  (func (export "i64.extend16_s") (param i64) (result i64)
+    local.get 0
+    i64.extend16_s)
+  (func (export "i64.extend32_s") (param i64) (result i64)
+    local.get 0
+    i64.extend32_s))
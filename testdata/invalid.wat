@@ -0,0 +1,8 @@
+(module
+  (func $ok (result i32)
+    i32.const 1)
+  (func $bad (result i32)
+    i32.const 1
+    i64.const 2
+    i32.add)
+  (export "bad" (func $bad)))
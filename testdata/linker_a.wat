@@ -0,0 +1,10 @@
+(module
+  (memory (export "memory") 1)
+  (global $g (export "g") (mut i32) (i32.const 10))
+  (table (export "table") 2 funcref)
+  (elem (i32.const 0) $get_g)
+  (func $get_g (export "get_g") (result i32)
+    global.get $g)
+  (func (export "load") (param i32) (result i32)
+    local.get 0
+    i32.load))
@@ -0,0 +1,17 @@
+(module
+  (import "a" "get_g" (func $get_g (result i32)))
+  (import "a" "memory" (memory 1))
+  (import "a" "g" (global $g (mut i32)))
+  (import "a" "table" (table 2 funcref))
+  (func (export "set_g") (param i32)
+    local.get 0
+    global.set $g)
+  (func (export "call_get_g") (result i32)
+    call $get_g)
+  (func (export "call_indirect") (result i32)
+    i32.const 0
+    call_indirect (result i32))
+  (func (export "store") (param i32 i32)
+    local.get 0
+    local.get 1
+    i32.store))
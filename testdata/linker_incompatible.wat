@@ -0,0 +1,2 @@
+(module
+  (import "a" "get_g" (func (param i32))))
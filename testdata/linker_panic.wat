@@ -0,0 +1,5 @@
+(module
+  (import "a" "call_add" (func $add (param i32) (result i32)))
+  (func (export "call_add") (param i32) (result i32)
+    local.get 0
+    call $add))
@@ -0,0 +1,2 @@
+(module
+  (import "a" "unknown" (func)))
@@ -0,0 +1,21 @@
+(module
+  (memory 1)
+  (data $hello "hello")
+  (data (i32.const 0) "wasm")
+  (func (export "init") (param i32 i32 i32)
+    local.get 0
+    local.get 1
+    local.get 2
+    memory.init $hello)
+  (func (export "drop")
+    data.drop $hello)
+  (func (export "copy") (param i32 i32 i32)
+    local.get 0
+    local.get 1
+    local.get 2
+    memory.copy)
+  (func (export "fill") (param i32 i32 i32)
+    local.get 0
+    local.get 1
+    local.get 2
+    memory.fill))
@@ -0,0 +1,7 @@
+(module
+  (memory 1 3)
+  (func (export "size") (result i32)
+    memory.size)
+  (func (export "grow") (param i32) (result i32)
+    local.get 0
+    memory.grow))
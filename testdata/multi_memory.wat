@@ -0,0 +1,14 @@
+(module
+  (memory $a 1)
+  (memory $b 1)
+  (export "memory" (memory $b))
+  (func (export "store") (param i32 i32)
+    local.get 0
+    local.get 1
+    i32.store $b)
+  (func (export "load_a") (param i32) (result i32)
+    local.get 0
+    i32.load)
+  (func (export "load_b") (param i32) (result i32)
+    local.get 0
+    i32.load $b offset=4))
@@ -0,0 +1,40 @@
+(module
+  (func (export "swap") (param i32 i32) (result i32 i32)
+    local.get 1
+    local.get 0)
+  (func (export "block") (param i32 i32) (result i32 i32)
+    local.get 0
+    local.get 1
+    block (param i32 i32) (result i32 i32)
+      i32.add
+      local.get 0
+    end)
+  (func (export "if") (param i32 i32) (result i32 i32)
+    local.get 0
+    local.get 1
+    if (param i32) (result i32 i32)
+      i32.const 1
+    else
+      i32.const 2
+    end)
+  (func (export "sum") (param i32) (result i32)
+    i32.const 0
+    local.get 0
+    loop (param i32 i32) (result i32)
+      local.set 0
+      local.get 0
+      i32.add
+      local.get 0
+      i32.const 1
+      i32.sub
+      local.get 0
+      i32.const 1
+      i32.ne
+      br_if 0
+      drop
+    end)
+  (func (export "br") (result i32 i32 i64)
+    i32.const 1
+    i32.const 2
+    i64.const 3
+    br 0))
@@ -0,0 +1,17 @@
+(module
+  (import "env" "host" (func $host (param i32) (result i32)))
+  (memory (export "memory") 1)
+  (global $next (mut i32) (i32.const 16))
+  (func (export "malloc") (param i32) (result i32)
+    global.get $next
+    global.get $next
+    local.get 0
+    i32.add
+    global.set $next)
+  (func (export "fail") (result i32)
+    unreachable)
+  (func (export "run") (param i32) (result i32)
+    i32.const 100
+    local.get 0
+    call $host
+    i32.add))
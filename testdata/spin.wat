@@ -0,0 +1,8 @@
+(module
+  (func $spin (export "spin")
+    (loop $l
+      br $l))
+  (func (export "spin_call")
+    call $spin)
+  (func (export "answer") (result i32)
+    i32.const 42))
@@ -0,0 +1,14 @@
+(module
+  (import "env" "init" (func $init (result i32)))
+  (memory 1)
+  (global $g (mut i32) (i32.const 0))
+  (data (i32.const 0) "hello")
+  (func $start
+    i32.const 0
+    i32.const 0x4a
+    i32.store8
+    call $init
+    global.set $g)
+  (func (export "get") (result i32)
+    global.get $g)
+  (start $start))
@@ -0,0 +1,12 @@
+(module
+  (type $t (func (result i32)))
+  (table $t0 5 funcref)
+  (table $t1 2 funcref)
+  (func $f0 (type $t) (i32.const 0))
+  (func $f1 (type $t) (i32.const 1))
+  (func $f2 (type $t) (i32.const 2))
+  (elem (i32.const 1) $f0 $f1)
+  (elem (table $t1) (i32.const 0) func $f2)
+  (elem (i32.const 3) funcref (ref.func $f2) (ref.null func))
+  (elem func $f0)
+  (elem declare func $f1))
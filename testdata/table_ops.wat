@@ -0,0 +1,48 @@
+(module
+  (table $ext 2 externref)
+  (table $fn 4 funcref)
+  (elem $fns func $one $two)
+  (func $one (result i32)
+    i32.const 1)
+  (func $two (result i32)
+    i32.const 2)
+  (func (export "get") (param i32) (result externref)
+    local.get 0
+    table.get $ext)
+  (func (export "set") (param i32 externref)
+    local.get 0
+    local.get 1
+    table.set $ext)
+  (func (export "size") (result i32)
+    table.size $ext)
+  (func (export "grow") (param externref i32) (result i32)
+    local.get 0
+    local.get 1
+    table.grow $ext)
+  (func (export "fill") (param i32 externref i32)
+    local.get 0
+    local.get 1
+    local.get 2
+    table.fill $ext)
+  (func (export "copy") (param i32 i32 i32)
+    local.get 0
+    local.get 1
+    local.get 2
+    table.copy $ext $ext)
+  (func (export "init") (param i32 i32 i32)
+    local.get 0
+    local.get 1
+    local.get 2
+    table.init $fn $fns)
+  (func (export "drop")
+    elem.drop $fns)
+  (func (export "is_null") (param i32) (result i32)
+    local.get 0
+    table.get $fn
+    ref.is_null)
+  (func (export "call") (param i32) (result i32)
+    local.get 0
+    call_indirect $fn (result i32))
+  (func (export "ref_func") (result i32)
+    ref.func $one
+    ref.is_null))
@@ -0,0 +1,22 @@
+(module
+  (import "env" "nop" (func $nop))
+  (memory 1)
+  (func $inner (param i32) (result i32)
+    local.get 0
+    i32.const 0
+    i32.div_u)
+  (func (export "div") (param i32) (result i32)
+    call $nop
+    i32.const 1
+    drop
+    local.get 0
+    call $inner)
+  (func (export "unreachable")
+    unreachable)
+  (func (export "load") (param i32) (result i32)
+    local.get 0
+    i32.load offset=0xffffffff)
+  (func (export "store") (param i32)
+    local.get 0
+    i32.const 0
+    i32.store offset=0xffffffff))
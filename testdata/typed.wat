@@ -0,0 +1,15 @@
+(module
+  (import "env" "scale" (func $scale (param i32 i64) (result f64)))
+  (func (export "call_scale") (param i32 i64) (result f64)
+    local.get 0
+    local.get 1
+    call $scale)
+  (func (export "div_u") (param i32 i32) (result i32)
+    local.get 0
+    local.get 1
+    i32.div_u)
+  (func (export "split") (param f32) (result f32 i32)
+    local.get 0
+    local.get 0
+    f32.const 0
+    f32.lt))
@@ -0,0 +1,19 @@
+package binary
+
+//go:generate stringer -type=ElementMode
+type ElementMode int
+
+const (
+	_ ElementMode = iota
+	ElementModeActive
+	ElementModePassive
+	ElementModeDeclarative
+)
+
+type Element struct {
+	Mode       ElementMode
+	TableIndex uint32
+	Offset     Expr
+	Type       RefType
+	Init       []Expr
+}
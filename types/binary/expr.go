@@ -1,38 +1,54 @@
 package binary
 
+import "fmt"
+
+// ErrInvalidValue is returned when a constant is used as a value of another type.
+var ErrInvalidValue = fmt.Errorf("invalid value")
+
 type Expr interface {
 	isExpr()
 }
 
 type ExprValue interface {
 	isExprValue()
-	Int() int
+	// Int returns the value of an integer constant. It fails with ErrInvalidValue for float constants.
+	Int() (int, error)
 }
 
 type ExprValueConstI32 int32
 
-func (ExprValueConstI32) isExpr()      {}
-func (ExprValueConstI32) isExprValue() {}
-func (e ExprValueConstI32) Int() int   { return int(e) }
+func (ExprValueConstI32) isExpr()             {}
+func (ExprValueConstI32) isExprValue()        {}
+func (e ExprValueConstI32) Int() (int, error) { return int(e), nil }
 
 type ExprValueConstI64 int64
 
-func (ExprValueConstI64) isExpr()      {}
-func (ExprValueConstI64) isExprValue() {}
-func (e ExprValueConstI64) Int() int   { return int(e) }
+func (ExprValueConstI64) isExpr()             {}
+func (ExprValueConstI64) isExprValue()        {}
+func (e ExprValueConstI64) Int() (int, error) { return int(e), nil }
 
 type ExprValueConstF32 [4]byte
 
-func (ExprValueConstF32) isExpr()      {}
-func (ExprValueConstF32) isExprValue() {}
-func (ExprValueConstF32) Int() int     { panic("int for f32 is not allowed") }
+func (ExprValueConstF32) isExpr()           {}
+func (ExprValueConstF32) isExprValue()      {}
+func (ExprValueConstF32) Int() (int, error) { return 0, fmt.Errorf("int for f32: %w", ErrInvalidValue) }
 
 type ExprValueConstF64 [8]byte
 
 func (ExprValueConstF64) isExpr()      {}
 func (ExprValueConstF64) isExprValue() {}
-func (e ExprValueConstF64) Int() int   { panic("int for f64 is not allowed") }
+func (e ExprValueConstF64) Int() (int, error) {
+	return 0, fmt.Errorf("int for f64: %w", ErrInvalidValue)
+}
 
 type ExprGlobalIndex uint32
 
 func (e ExprGlobalIndex) isExpr() {}
+
+type ExprRefNull RefType
+
+func (e ExprRefNull) isExpr() {}
+
+type ExprRefFunc uint32
+
+func (e ExprRefFunc) isExpr() {}
@@ -2,6 +2,7 @@ package binary
 
 import (
 	"io"
+	"slices"
 
 	"github.com/Warashi/wasmium/opcode"
 )
@@ -21,6 +22,10 @@ type FuncType struct {
 	Results []ValueType
 }
 
+func (f FuncType) Equal(other FuncType) bool {
+	return slices.Equal(f.Params, other.Params) && slices.Equal(f.Results, other.Results)
+}
+
 type ValueType byte
 
 const (
@@ -28,6 +33,9 @@ const (
 	ValueTypeI64 ValueType = 0x7e
 	ValueTypeF32 ValueType = 0x7d
 	ValueTypeF64 ValueType = 0x7c
+
+	ValueTypeFuncRef   = ValueType(RefTypeFunc)
+	ValueTypeExternRef = ValueType(RefTypeExtern)
 )
 
 type FunctionLocal struct {
@@ -45,6 +53,24 @@ type ImportDescFunc struct {
 
 func (i ImportDescFunc) isImportDesc() {}
 
+type ImportDescTable struct {
+	Type TableType
+}
+
+func (i ImportDescTable) isImportDesc() {}
+
+type ImportDescMemory struct {
+	Memory Memory
+}
+
+func (i ImportDescMemory) isImportDesc() {}
+
+type ImportDescGlobal struct {
+	Type GlobalType
+}
+
+func (i ImportDescGlobal) isImportDesc() {}
+
 type Import struct {
 	Module string
 	Field  string
@@ -52,8 +78,9 @@ type Import struct {
 }
 
 type Limits struct {
-	Min uint32
-	Max uint32
+	Min    uint32
+	Max    uint32
+	HasMax bool
 }
 
 type Memory struct {
@@ -82,17 +109,22 @@ type Block struct {
 
 type BlockType interface {
 	isBlockType()
-	ResultCount() int
 }
 
 type BlockTypeVoid struct{}
 
-func (b BlockTypeVoid) isBlockType()     {}
-func (b BlockTypeVoid) ResultCount() int { return 0 }
+func (b BlockTypeVoid) isBlockType() {}
 
 type BlockTypeValue struct {
 	ValueTypes []ValueType
 }
 
-func (b BlockTypeValue) isBlockType()     {}
-func (b BlockTypeValue) ResultCount() int { return len(b.ValueTypes) }
+func (b BlockTypeValue) isBlockType() {}
+
+// BlockTypeIndex is a block type given by an index into the type section.
+// It allows blocks to take parameters and return multiple results.
+type BlockTypeIndex struct {
+	Index uint32
+}
+
+func (b BlockTypeIndex) isBlockType() {}
@@ -2,7 +2,7 @@ package binary
 
 type Global struct {
 	Type     GlobalType
-	InitExpr ExprValue
+	InitExpr Expr
 }
 
 type GlobalType struct {
@@ -3,13 +3,48 @@ package instruction
 import (
 	"fmt"
 
+	"github.com/Warashi/wasmium/opcode"
 	"github.com/Warashi/wasmium/types/binary"
 	"github.com/Warashi/wasmium/types/runtime"
 )
 
 var ErrInvalidInstruction = fmt.Errorf("invalid instruction")
 
-func Convert(insts []binary.Instruction) ([]runtime.Instruction, error) {
+// Block is implemented by the instructions that start a block: block, loop and if.
+type Block interface {
+	BlockType() binary.BlockType
+	// Resolve sets the arities of the block and the addresses of its else and end.
+	// elseAddress is -1 if the block has no else.
+	Resolve(params, results, elseAddress, end int)
+}
+
+// BlockEnd is implemented by else and end, which finish the block started by a Block.
+type BlockEnd interface {
+	// Resolve sets the number of results of the block and the address of its end.
+	Resolve(results, end int)
+}
+
+// Branch is implemented by the instructions that branch to labels: br, br_if and br_table.
+type Branch interface {
+	// Labels returns the relative depths of the labels the instruction may branch to.
+	Labels() []uint32
+	// Resolve sets the targets of the labels in the order of Labels.
+	Resolve(targets []runtime.BranchTarget)
+}
+
+// block is a structured instruction matched with its else and end.
+type block struct {
+	start           int
+	loop            bool
+	params, results int
+	elseAddress     int
+	end             int
+}
+
+// Convert converts the body of a function for execution.
+// It resolves the else and end of every block and the targets of every branch,
+// so that they are not searched for at runtime. types is the type section of the module.
+func Convert(insts []binary.Instruction, types []binary.FuncType) ([]runtime.Instruction, error) {
 	result := make([]runtime.Instruction, 0, len(insts))
 	for _, inst := range insts {
 		o, ok := inst.(runtime.Instruction)
@@ -19,5 +54,117 @@ func Convert(insts []binary.Instruction) ([]runtime.Instruction, error) {
 		result = append(result, o)
 	}
 
+	blocks, err := matchBlocks(insts, types)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: the function body is the outermost label, and a branch to it acts as return.
+	ctrls := []*block{{start: -1}}
+	for pc, inst := range insts {
+		if len(ctrls) == 0 {
+			return nil, fmt.Errorf("%w: instruction after the end of the function at %d", ErrInvalidInstruction, pc)
+		}
+		switch inst.Opcode() {
+		case opcode.OpcodeBlock, opcode.OpcodeLoop, opcode.OpcodeIf:
+			b := blocks[pc]
+			inst.(Block).Resolve(b.params, b.results, b.elseAddress, b.end)
+			ctrls = append(ctrls, b)
+		case opcode.OpcodeElse:
+			b := ctrls[len(ctrls)-1]
+			inst.(BlockEnd).Resolve(b.results, b.end)
+		case opcode.OpcodeEnd:
+			b := ctrls[len(ctrls)-1]
+			inst.(BlockEnd).Resolve(b.results, b.end)
+			ctrls = ctrls[:len(ctrls)-1]
+		case opcode.OpcodeBr, opcode.OpcodeBrIf, opcode.OpcodeBrTable:
+			branch := inst.(Branch)
+			labels := branch.Labels()
+			targets := make([]runtime.BranchTarget, 0, len(labels))
+			for _, level := range labels {
+				if len(ctrls) <= int(level) {
+					return nil, fmt.Errorf("%w: unknown label %d at %d", ErrInvalidInstruction, level, pc)
+				}
+				targets = append(targets, ctrls[len(ctrls)-1-int(level)].target())
+			}
+			branch.Resolve(targets)
+		}
+	}
+
 	return result, nil
 }
+
+// matchBlocks returns the blocks started in insts by their addresses.
+func matchBlocks(insts []binary.Instruction, types []binary.FuncType) (map[int]*block, error) {
+	blocks := make(map[int]*block)
+
+	var open []*block
+	for pc, inst := range insts {
+		switch inst.Opcode() {
+		case opcode.OpcodeBlock, opcode.OpcodeLoop, opcode.OpcodeIf:
+			b, ok := inst.(Block)
+			if !ok {
+				return nil, fmt.Errorf("%w: %T", ErrInvalidInstruction, inst)
+			}
+			params, results, err := blockArity(b.BlockType(), types)
+			if err != nil {
+				return nil, err
+			}
+			open = append(open, &block{
+				start:       pc,
+				loop:        inst.Opcode() == opcode.OpcodeLoop,
+				params:      params,
+				results:     results,
+				elseAddress: -1,
+			})
+		case opcode.OpcodeElse:
+			if len(open) == 0 || open[len(open)-1].elseAddress != -1 {
+				return nil, fmt.Errorf("%w: unexpected else at %d", ErrInvalidInstruction, pc)
+			}
+			open[len(open)-1].elseAddress = pc
+		case opcode.OpcodeEnd:
+			// NOTE: the last end finishes the function body, which is not a block.
+			if len(open) == 0 {
+				continue
+			}
+			b := open[len(open)-1]
+			b.end = pc
+			blocks[b.start] = b
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) != 0 {
+		return nil, fmt.Errorf("%w: block at %d has no end", ErrInvalidInstruction, open[len(open)-1].start)
+	}
+
+	return blocks, nil
+}
+
+// target returns the destination of a branch to the label of b.
+func (b *block) target() runtime.BranchTarget {
+	switch {
+	case b.start < 0:
+		return runtime.BranchTarget{Return: true}
+	case b.loop:
+		return runtime.BranchTarget{Loop: true, ProgramCounter: b.start, Arity: b.params}
+	default:
+		return runtime.BranchTarget{ProgramCounter: b.end, Arity: b.results}
+	}
+}
+
+// blockArity returns the number of parameters and results of a block type.
+func blockArity(bt binary.BlockType, types []binary.FuncType) (params, results int, err error) {
+	switch bt := bt.(type) {
+	case binary.BlockTypeVoid:
+		return 0, 0, nil
+	case binary.BlockTypeValue:
+		return 0, len(bt.ValueTypes), nil
+	case binary.BlockTypeIndex:
+		if len(types) <= int(bt.Index) {
+			return 0, 0, fmt.Errorf("%w: unknown block type %d", ErrInvalidInstruction, bt.Index)
+		}
+		return len(types[bt.Index].Params), len(types[bt.Index].Results), nil
+	default:
+		return 0, 0, fmt.Errorf("%w: unexpected block type %T", ErrInvalidInstruction, bt)
+	}
+}
@@ -1,9 +1,36 @@
 package runtime
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/Warashi/wasmium/types/binary"
+)
 
 var (
 	ErrOutOfBounds       = fmt.Errorf("out of bounds")
 	ErrMemoryOutOfBounds = fmt.Errorf("memory out of bounds")
-	ErrInvalidValue      = fmt.Errorf("invalid value")
+	// ErrInvalidValue is shared with the constants of the binary format.
+	ErrInvalidValue = binary.ErrInvalidValue
+	ErrTypeMismatch = fmt.Errorf("type mismatch")
+
+	ErrUnreachable    = fmt.Errorf("unreachable")
+	ErrStackExhausted = fmt.Errorf("call stack exhausted")
+	ErrInternal       = fmt.Errorf("internal error")
+
+	ErrUndefinedElement         = fmt.Errorf("undefined element")
+	ErrUninitializedElement     = fmt.Errorf("uninitialized element")
+	ErrIndirectCallTypeMismatch = fmt.Errorf("indirect call type mismatch")
+	ErrTableOutOfBounds         = fmt.Errorf("out of bounds table access")
+
+	ErrIntegerDivideByZero = fmt.Errorf("integer divide by zero")
+	ErrIntegerOverflow     = fmt.Errorf("integer overflow")
+
+	ErrInvalidConversionToInteger = fmt.Errorf("invalid conversion to integer")
+
+	ErrUnknownImport          = fmt.Errorf("unknown import")
+	ErrIncompatibleImportType = fmt.Errorf("incompatible import type")
+	ErrHostResultMismatch     = fmt.Errorf("host function results do not match its type")
+
+	ErrInterrupted = fmt.Errorf("interrupted")
+	ErrOutOfFuel   = fmt.Errorf("out of fuel")
 )
@@ -9,6 +9,8 @@ type Instruction interface {
 }
 
 type Frame struct {
+	// FuncIndex is the index of the executing function in the module that defines it.
+	FuncIndex      uint32
 	ProgramCounter int
 	StackPointer   int
 	Instructions   []Instruction
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/Warashi/wasmium/stack"
+	"github.com/Warashi/wasmium/types/binary"
 )
 
 var (
@@ -29,9 +30,14 @@ type Runtime interface {
 	StackUnwind(stackPointer, arity int) error
 
 	Func(i int) (FuncInst, error)
+	FuncType(i int) (binary.FuncType, error)
+	Table(i int) (*TableInst, error)
+	Element(i int) (*ElementInst, error)
 	InvokeInternal(InternalFuncInst) ([]Value, error)
 	InvokeExternal(ExternalFuncInst) ([]Value, error)
 
+	Memory(n int) (*MemoryInst, error)
+	Data(i int) (*DataInst, error)
 	WriteMemoryAt(n int, data []byte, offset int64) (int, error)
 	ReadMemoryAt(n int, buf []byte, offset int64) (int, error)
 }
@@ -1,6 +1,8 @@
 package runtime
 
 import (
+	"slices"
+
 	"github.com/Warashi/wasmium/types/binary"
 )
 
@@ -9,8 +11,13 @@ type FuncInst interface {
 }
 
 type InternalFuncInst struct {
+	// Index is the index of the function in the module that defines it.
+	Index    uint32
 	FuncType binary.FuncType
 	Code     Func
+	// Instance is the instance that defines the function.
+	// A call from another instance is executed by it.
+	Instance Runtime
 }
 
 func (f InternalFuncInst) isFuncInst() {}
@@ -24,6 +31,8 @@ type ExternalFuncInst struct {
 	Module   string
 	Func     string
 	FuncType binary.FuncType
+	// Instance is the instance that imports the function and resolves it by name.
+	Instance Runtime
 }
 
 func (f ExternalFuncInst) isFuncInst() {}
@@ -42,25 +51,132 @@ func (m ModuleInst) Exported(name string) (ExportInst, bool) {
 	return e, ok
 }
 
+const (
+	PageSize = 65536 // 64 Ki
+	MaxPages = 65536 // 4 Gi in total
+)
+
 type MemoryInst struct {
-	Data []byte
-	Max  uint32
+	Data   []byte
+	Max    uint32
+	HasMax bool
+	// Limit is the maximum number of pages allowed by the host. Zero means no limit.
+	Limit uint32
+}
+
+// NewMemoryInst creates a zero-filled memory with the given limits in pages.
+// A memory created by the host can be shared with modules that import it.
+func NewMemoryInst(limits binary.Limits) *MemoryInst {
+	return &MemoryInst{
+		Data:   make([]byte, int(limits.Min)*PageSize),
+		Max:    limits.Max,
+		HasMax: limits.HasMax,
+	}
+}
+
+// Size returns the current size of the memory in pages.
+func (m *MemoryInst) Size() uint32 {
+	return uint32(len(m.Data) / PageSize)
+}
+
+// Grow grows the memory by delta pages and returns the previous size in pages.
+// It returns -1 if the memory would exceed its maximum, MaxPages or the host limit.
+func (m *MemoryInst) Grow(delta uint32) int32 {
+	size := m.Size()
+
+	max := uint32(MaxPages)
+	if m.HasMax {
+		max = min(max, m.Max)
+	}
+	if m.Limit > 0 {
+		max = min(max, m.Limit)
+	}
+
+	if uint64(size)+uint64(delta) > uint64(max) {
+		return -1
+	}
+
+	m.Data = append(m.Data, make([]byte, int(delta)*PageSize)...)
+
+	return int32(size)
 }
 
 func (m *MemoryInst) WriteAt(p []byte, off int64) (n int, err error) {
-	if int64(len(m.Data)) < off+int64(len(p)) {
+	if off < 0 || int64(len(m.Data)) < off+int64(len(p)) {
 		return 0, ErrMemoryOutOfBounds
 	}
 	return copy(m.Data[off:], p), nil
 }
 
 func (m *MemoryInst) ReadAt(p []byte, off int64) (n int, err error) {
-	if int64(len(m.Data)) < off+int64(len(p)) {
+	if off < 0 || int64(len(m.Data)) < off+int64(len(p)) {
 		return 0, ErrMemoryOutOfBounds
 	}
 	return copy(p, m.Data[off:]), nil
 }
 
+// DataInst is a data segment kept for memory.init.
+// Its Data becomes empty once the segment is dropped.
+type DataInst struct {
+	Data []byte
+}
+
+// ElementInst is an element segment kept for table.init.
+// Its Elements becomes empty once the segment is dropped.
+type ElementInst struct {
+	Type     binary.RefType
+	Elements []Ref
+}
+
+type TableInst struct {
+	Type     binary.RefType
+	Elements []Ref
+	Max      uint32
+	HasMax   bool
+}
+
+// MaxTableSize is the implementation limit on the number of table elements.
+const MaxTableSize = 1 << 24
+
+// NewTableInst creates a table of the given type filled with null references.
+// A table created by the host can be shared with modules that import it.
+func NewTableInst(t binary.TableType) *TableInst {
+	elements := make([]Ref, t.Limits.Min)
+	for i := range elements {
+		elements[i] = NullRef(t.ElementType)
+	}
+	return &TableInst{
+		Type:     t.ElementType,
+		Elements: elements,
+		Max:      t.Limits.Max,
+		HasMax:   t.Limits.HasMax,
+	}
+}
+
+// Size returns the current number of elements in the table.
+func (t *TableInst) Size() uint32 {
+	return uint32(len(t.Elements))
+}
+
+// Grow grows the table by delta elements initialized with init and returns the previous size.
+// It returns -1 if the table would exceed its maximum.
+func (t *TableInst) Grow(delta uint32, init Ref) int32 {
+	size := t.Size()
+
+	max := uint32(MaxTableSize)
+	if t.HasMax {
+		max = min(max, t.Max)
+	}
+
+	if uint64(size)+uint64(delta) > uint64(max) {
+		return -1
+	}
+
+	t.Elements = append(t.Elements, slices.Repeat([]Ref{init}, int(delta))...)
+
+	return int32(size)
+}
+
 type GlobalInst struct {
 	Value   Value
 	Mutable bool
@@ -0,0 +1,122 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TrapCode classifies the cause of a trap.
+type TrapCode int
+
+const (
+	TrapUnknown TrapCode = iota
+	TrapUnreachable
+	TrapMemoryOutOfBounds
+	TrapTableOutOfBounds
+	TrapIntegerDivideByZero
+	TrapIntegerOverflow
+	TrapInvalidConversionToInteger
+	TrapIndirectCallTypeMismatch
+	TrapUndefinedElement
+	TrapUninitializedElement
+	TrapStackExhausted
+	TrapHostResultMismatch
+	TrapOutOfFuel
+	TrapInterrupted
+	TrapInternal
+)
+
+func (c TrapCode) String() string {
+	switch c {
+	case TrapUnreachable:
+		return "unreachable"
+	case TrapMemoryOutOfBounds:
+		return "out of bounds memory access"
+	case TrapTableOutOfBounds:
+		return "out of bounds table access"
+	case TrapIntegerDivideByZero:
+		return "integer divide by zero"
+	case TrapIntegerOverflow:
+		return "integer overflow"
+	case TrapInvalidConversionToInteger:
+		return "invalid conversion to integer"
+	case TrapIndirectCallTypeMismatch:
+		return "indirect call type mismatch"
+	case TrapUndefinedElement:
+		return "undefined element"
+	case TrapUninitializedElement:
+		return "uninitialized element"
+	case TrapStackExhausted:
+		return "call stack exhausted"
+	case TrapHostResultMismatch:
+		return "host result mismatch"
+	case TrapOutOfFuel:
+		return "out of fuel"
+	case TrapInterrupted:
+		return "interrupted"
+	case TrapInternal:
+		return "internal error"
+	default:
+		return "unknown"
+	}
+}
+
+// trapCodes maps the errors returned by instructions to trap codes.
+var trapCodes = []struct {
+	err  error
+	code TrapCode
+}{
+	{ErrUnreachable, TrapUnreachable},
+	{ErrMemoryOutOfBounds, TrapMemoryOutOfBounds},
+	{ErrTableOutOfBounds, TrapTableOutOfBounds},
+	{ErrIntegerDivideByZero, TrapIntegerDivideByZero},
+	{ErrIntegerOverflow, TrapIntegerOverflow},
+	{ErrInvalidConversionToInteger, TrapInvalidConversionToInteger},
+	{ErrIndirectCallTypeMismatch, TrapIndirectCallTypeMismatch},
+	{ErrUndefinedElement, TrapUndefinedElement},
+	{ErrUninitializedElement, TrapUninitializedElement},
+	{ErrStackExhausted, TrapStackExhausted},
+	{ErrHostResultMismatch, TrapHostResultMismatch},
+	{ErrOutOfFuel, TrapOutOfFuel},
+	{ErrInterrupted, TrapInterrupted},
+	{ErrInternal, TrapInternal},
+}
+
+// TrapCodeOf returns the trap code for err, or TrapUnknown if err is not a known cause.
+func TrapCodeOf(err error) TrapCode {
+	for _, c := range trapCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return TrapUnknown
+}
+
+// TraceFrame is a wasm function frame in the backtrace of a trap.
+type TraceFrame struct {
+	// FuncIndex is the index of the function in the module that defines it.
+	FuncIndex uint32
+	// Offset is the index of the executing instruction in the function body.
+	Offset int
+}
+
+func (f TraceFrame) String() string {
+	return fmt.Sprintf("func[%d]+%d", f.FuncIndex, f.Offset)
+}
+
+// Trap is the error returned when wasm execution traps.
+type Trap struct {
+	Code TrapCode
+	// Trace lists the wasm frames active at the trap, innermost first.
+	Trace []TraceFrame
+	// Err is the underlying error.
+	Err error
+}
+
+func (t *Trap) Error() string {
+	return fmt.Sprintf("wasm trap: %s: %v", t.Code, t.Err)
+}
+
+func (t *Trap) Unwrap() error {
+	return t.Err
+}
@@ -3,6 +3,9 @@ package runtime
 import (
 	"encoding/binary"
 	"fmt"
+	"math"
+
+	tbinary "github.com/Warashi/wasmium/types/binary"
 )
 
 type ValueType byte
@@ -12,6 +15,9 @@ const (
 	ValueTypeI64 ValueType = 0x7E
 	ValueTypeF32 ValueType = 0x7D
 	ValueTypeF64 ValueType = 0x7C
+
+	ValueTypeFuncRef   ValueType = 0x70
+	ValueTypeExternRef ValueType = 0x6F
 )
 
 func (t ValueType) String() string {
@@ -24,17 +30,33 @@ func (t ValueType) String() string {
 		return "f32"
 	case ValueTypeF64:
 		return "f64"
+	case ValueTypeFuncRef:
+		return "funcref"
+	case ValueTypeExternRef:
+		return "externref"
 	default:
 		return "unknown"
 	}
 }
 
+// ValueFrom converts a Go value to the corresponding wasm value.
+// Unsigned integers keep their bit pattern and bool becomes an i32 of 0 or 1.
 func ValueFrom(v any) (Value, error) {
 	switch v := v.(type) {
+	case Value:
+		return v, nil
 	case int32:
 		return ValueI32(v), nil
+	case uint32:
+		return ValueI32(v), nil
 	case int64:
 		return ValueI64(v), nil
+	case uint64:
+		return ValueI64(v), nil
+	case float32:
+		return NewValueF32(v), nil
+	case float64:
+		return NewValueF64(v), nil
 	case bool:
 		if v {
 			return ValueI32(1), nil
@@ -44,33 +66,83 @@ func ValueFrom(v any) (Value, error) {
 	return nil, fmt.Errorf("unsupported type %T", v)
 }
 
+// ValueTo converts a wasm value to a Go value of type T, the inverse of ValueFrom.
+func ValueTo[T any](v Value) (T, error) {
+	var t T
+	var ok bool
+	switch p := any(&t).(type) {
+	case *int32:
+		var i ValueI32
+		i, ok = v.(ValueI32)
+		*p = int32(i)
+	case *uint32:
+		var i ValueI32
+		i, ok = v.(ValueI32)
+		*p = uint32(i)
+	case *int64:
+		var i ValueI64
+		i, ok = v.(ValueI64)
+		*p = int64(i)
+	case *uint64:
+		var i ValueI64
+		i, ok = v.(ValueI64)
+		*p = uint64(i)
+	case *float32:
+		var f ValueF32
+		f, ok = v.(ValueF32)
+		*p = f.Float32()
+	case *float64:
+		var f ValueF64
+		f, ok = v.(ValueF64)
+		*p = f.Float64()
+	case *bool:
+		var i ValueI32
+		i, ok = v.(ValueI32)
+		*p = i != 0
+	default:
+		t, ok = v.(T)
+	}
+	if !ok {
+		return t, fmt.Errorf("cannot convert %T to %T", v, t)
+	}
+	return t, nil
+}
+
 type Value interface {
 	isValue()
 	Type() ValueType
-	Int() int
-	Bool() bool
+	// Int returns the value of an integer. It fails with ErrInvalidValue for other values.
+	Int() (int, error)
+	// Bool reports whether an integer is non-zero. It fails with ErrInvalidValue for other values.
+	Bool() (bool, error)
 }
 
 type ValueI32 int32
 
-func (ValueI32) isValue()        {}
-func (ValueI32) Type() ValueType { return ValueTypeI32 }
-func (v ValueI32) Int() int      { return int(v) }
-func (v ValueI32) Bool() bool    { return v != 0 }
+func (ValueI32) isValue()              {}
+func (ValueI32) Type() ValueType       { return ValueTypeI32 }
+func (v ValueI32) Int() (int, error)   { return int(v), nil }
+func (v ValueI32) Bool() (bool, error) { return v != 0, nil }
 
 type ValueI64 int64
 
-func (ValueI64) isValue()        {}
-func (ValueI64) Type() ValueType { return ValueTypeI64 }
-func (v ValueI64) Int() int      { return int(v) }
-func (v ValueI64) Bool() bool    { return v != 0 }
+func (ValueI64) isValue()              {}
+func (ValueI64) Type() ValueType       { return ValueTypeI64 }
+func (v ValueI64) Int() (int, error)   { return int(v), nil }
+func (v ValueI64) Bool() (bool, error) { return v != 0, nil }
 
 type ValueF32 [4]byte
 
-func (ValueF32) isValue()        {}
-func (ValueF32) Type() ValueType { return ValueTypeF32 }
-func (ValueF32) Int() int        { panic("int for f32 is not allowed") }
-func (ValueF32) Bool() bool      { panic("bool for f32 is not allowed") }
+func NewValueF32(f float32) ValueF32 {
+	var v ValueF32
+	binary.LittleEndian.PutUint32(v[:], math.Float32bits(f))
+	return v
+}
+
+func (ValueF32) isValue()            {}
+func (ValueF32) Type() ValueType     { return ValueTypeF32 }
+func (ValueF32) Int() (int, error)   { return 0, fmt.Errorf("int for f32: %w", ErrInvalidValue) }
+func (ValueF32) Bool() (bool, error) { return false, fmt.Errorf("bool for f32: %w", ErrInvalidValue) }
 func (v ValueF32) Float32() float32 {
 	var f float32
 	binary.Decode(v[:], binary.LittleEndian, &f)
@@ -79,60 +151,113 @@ func (v ValueF32) Float32() float32 {
 
 type ValueF64 [8]byte
 
-func (ValueF64) isValue()        {}
-func (ValueF64) Type() ValueType { return ValueTypeF64 }
-func (ValueF64) Int() int        { panic("int for f64 is not allowed") }
-func (ValueF64) Bool() bool      { panic("bool for f64 is not allowed") }
+func NewValueF64(f float64) ValueF64 {
+	var v ValueF64
+	binary.LittleEndian.PutUint64(v[:], math.Float64bits(f))
+	return v
+}
+
+func (ValueF64) isValue()            {}
+func (ValueF64) Type() ValueType     { return ValueTypeF64 }
+func (ValueF64) Int() (int, error)   { return 0, fmt.Errorf("int for f64: %w", ErrInvalidValue) }
+func (ValueF64) Bool() (bool, error) { return false, fmt.Errorf("bool for f64: %w", ErrInvalidValue) }
 func (v ValueF64) Float64() float64 {
 	var f float64
 	binary.Decode(v[:], binary.LittleEndian, &f)
 	return f
 }
 
-type LabelKind int
+// Ref is a reference value, either ValueFuncRef or ValueExternRef.
+type Ref interface {
+	Value
+	IsNull() bool
+}
 
-const (
-	LabelKindBlock LabelKind = iota
-	LabelKindLoop
-	LabelKindIf
-)
+// ValueFuncRef is a reference to a function. The zero value is the null reference.
+type ValueFuncRef struct {
+	Func FuncInst
+}
 
-type Label struct {
-	kind           LabelKind
-	start          int
-	programCounter int
-	stackPointer   int
-	arity          int
-}
-
-func NewLabel(kind LabelKind, start, pc, sp, arity int) Label {
-	return Label{
-		kind:           kind,
-		start:          start,
-		programCounter: pc,
-		stackPointer:   sp,
-		arity:          arity,
+func (ValueFuncRef) isValue()        {}
+func (ValueFuncRef) Type() ValueType { return ValueTypeFuncRef }
+func (ValueFuncRef) Int() (int, error) {
+	return 0, fmt.Errorf("int for funcref: %w", ErrInvalidValue)
+}
+func (ValueFuncRef) Bool() (bool, error) {
+	return false, fmt.Errorf("bool for funcref: %w", ErrInvalidValue)
+}
+func (v ValueFuncRef) IsNull() bool { return v.Func == nil }
+
+// ValueExternRef is an opaque reference to a host value. The zero value is the null reference.
+type ValueExternRef struct {
+	Ref any
+}
+
+func (ValueExternRef) isValue()        {}
+func (ValueExternRef) Type() ValueType { return ValueTypeExternRef }
+func (ValueExternRef) Int() (int, error) {
+	return 0, fmt.Errorf("int for externref: %w", ErrInvalidValue)
+}
+func (ValueExternRef) Bool() (bool, error) {
+	return false, fmt.Errorf("bool for externref: %w", ErrInvalidValue)
+}
+func (v ValueExternRef) IsNull() bool { return v.Ref == nil }
+
+// NullRef returns the null reference of the given reference type.
+func NullRef(t tbinary.RefType) Ref {
+	if t == tbinary.RefTypeExtern {
+		return ValueExternRef{}
 	}
+	return ValueFuncRef{}
 }
 
-func (l Label) Kind() LabelKind {
-	return l.kind
+// ZeroValue returns the default value of the given value type.
+func ZeroValue(t tbinary.ValueType) (Value, error) {
+	switch t {
+	case tbinary.ValueTypeI32:
+		return ValueI32(0), nil
+	case tbinary.ValueTypeI64:
+		return ValueI64(0), nil
+	case tbinary.ValueTypeF32:
+		return ValueF32{}, nil
+	case tbinary.ValueTypeF64:
+		return ValueF64{}, nil
+	case tbinary.ValueTypeFuncRef:
+		return ValueFuncRef{}, nil
+	case tbinary.ValueTypeExternRef:
+		return ValueExternRef{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type: %v", t)
+	}
 }
 
-func (l Label) Start() int {
-	return l.start
+// Label is a block entered by a frame.
+// The control-flow targets of blocks and branches are resolved by instruction.Convert,
+// so a label only records the operand stack height to unwind to.
+type Label struct {
+	stackPointer int
 }
 
-func (l Label) ProgramCounter() int {
-	return l.programCounter
+// NewLabel creates a label. sp is the stack height below the block parameters.
+func NewLabel(sp int) Label {
+	return Label{stackPointer: sp}
 }
 
 func (l Label) StackPointer() int {
 	return l.stackPointer
 }
 
-func (l Label) Arity() int {
-	return l.arity
+// BranchTarget is the resolved destination of a branch to a label.
+type BranchTarget struct {
+	// Return is set when the label is the function body, so the branch acts as return.
+	Return bool
+	// Loop is set when the label is a loop, which keeps its label to be re-entered.
+	Loop bool
+	// ProgramCounter is the address the branch continues after: the loop instruction or the end of the block.
+	ProgramCounter int
+	// Arity is the number of values carried by the branch:
+	// the parameters of a loop, or the results of any other block.
+	Arity int
 }
 
 func writeValue(buf []byte, v Value) (int, error) {
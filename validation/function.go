@@ -0,0 +1,648 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Warashi/wasmium/instruction"
+	"github.com/Warashi/wasmium/opcode"
+	tbinary "github.com/Warashi/wasmium/types/binary"
+)
+
+// unknown is the type of an operand popped from the stack of unreachable code.
+// It matches every type.
+const unknown tbinary.ValueType = 0
+
+var errTypeMismatch = errors.New("type mismatch")
+
+// ctrlFrame is a structured control instruction being validated.
+type ctrlFrame struct {
+	opcode      opcode.Opcode
+	params      []tbinary.ValueType
+	results     []tbinary.ValueType
+	height      int
+	unreachable bool
+}
+
+// labelTypes returns the types of the operands a branch to the frame takes.
+func (f *ctrlFrame) labelTypes() []tbinary.ValueType {
+	if f.opcode == opcode.OpcodeLoop {
+		return f.params
+	}
+	return f.results
+}
+
+// funcValidator validates a function body with the algorithm in the appendix of the specification.
+type funcValidator struct {
+	*context
+	params []tbinary.ValueType
+	locals []tbinary.FunctionLocal
+	vals   []tbinary.ValueType
+	ctrls  []ctrlFrame
+}
+
+func (c *context) validateFunc(index uint32, funcType tbinary.FuncType, body tbinary.Function) error {
+	var count uint64
+	for _, local := range body.Locals {
+		count += uint64(local.TypeCount)
+	}
+	if maxLocals < uint64(len(funcType.Params))+count {
+		return &Error{FuncIndex: index, Err: fmt.Errorf("too many locals: more than %d", maxLocals)}
+	}
+
+	v := &funcValidator{
+		context: c,
+		params:  funcType.Params,
+		locals:  body.Locals,
+	}
+
+	v.pushCtrl(opcode.OpcodeBlock, nil, funcType.Results)
+	for i, inst := range body.Code {
+		if len(v.ctrls) == 0 {
+			return &Error{FuncIndex: index, Offset: i, Err: fmt.Errorf("unexpected instruction after the end of the function")}
+		}
+		if err := v.validate(inst); err != nil {
+			return &Error{FuncIndex: index, Offset: i, Err: err}
+		}
+	}
+	if len(v.ctrls) != 0 {
+		return &Error{FuncIndex: index, Offset: len(body.Code), Err: fmt.Errorf("unexpected end of the function")}
+	}
+
+	return nil
+}
+
+func (v *funcValidator) pushVal(t tbinary.ValueType) {
+	v.vals = append(v.vals, t)
+}
+
+func (v *funcValidator) pushVals(ts []tbinary.ValueType) {
+	v.vals = append(v.vals, ts...)
+}
+
+func (v *funcValidator) popVal() (tbinary.ValueType, error) {
+	frame := &v.ctrls[len(v.ctrls)-1]
+	if len(v.vals) == frame.height {
+		if frame.unreachable {
+			return unknown, nil
+		}
+		return unknown, fmt.Errorf("%w: operand stack is empty", errTypeMismatch)
+	}
+	t := v.vals[len(v.vals)-1]
+	v.vals = v.vals[:len(v.vals)-1]
+	return t, nil
+}
+
+func (v *funcValidator) popExpected(want tbinary.ValueType) (tbinary.ValueType, error) {
+	got, err := v.popVal()
+	if err != nil {
+		return unknown, err
+	}
+	if got != want && got != unknown && want != unknown {
+		return unknown, fmt.Errorf("%w: expected %s, got %s", errTypeMismatch, typeName(want), typeName(got))
+	}
+	return got, nil
+}
+
+func (v *funcValidator) popVals(ts []tbinary.ValueType) ([]tbinary.ValueType, error) {
+	popped := make([]tbinary.ValueType, len(ts))
+	for i := len(ts) - 1; 0 <= i; i-- {
+		t, err := v.popExpected(ts[i])
+		if err != nil {
+			return nil, err
+		}
+		popped[i] = t
+	}
+	return popped, nil
+}
+
+func (v *funcValidator) popRef() (tbinary.ValueType, error) {
+	t, err := v.popVal()
+	if err != nil {
+		return unknown, err
+	}
+	if !isRef(t) && t != unknown {
+		return unknown, fmt.Errorf("%w: expected a reference, got %s", errTypeMismatch, typeName(t))
+	}
+	return t, nil
+}
+
+func (v *funcValidator) pushCtrl(op opcode.Opcode, params, results []tbinary.ValueType) {
+	v.ctrls = append(v.ctrls, ctrlFrame{
+		opcode:  op,
+		params:  params,
+		results: results,
+		height:  len(v.vals),
+	})
+	v.pushVals(params)
+}
+
+func (v *funcValidator) popCtrl() (ctrlFrame, error) {
+	frame := v.ctrls[len(v.ctrls)-1]
+	if _, err := v.popVals(frame.results); err != nil {
+		return ctrlFrame{}, err
+	}
+	if len(v.vals) != frame.height {
+		return ctrlFrame{}, fmt.Errorf("%w: %d values remain on the operand stack", errTypeMismatch, len(v.vals)-frame.height)
+	}
+	v.ctrls = v.ctrls[:len(v.ctrls)-1]
+	return frame, nil
+}
+
+func (v *funcValidator) setUnreachable() {
+	frame := &v.ctrls[len(v.ctrls)-1]
+	v.vals = v.vals[:frame.height]
+	frame.unreachable = true
+}
+
+func (v *funcValidator) label(level uint32) (*ctrlFrame, error) {
+	if len(v.ctrls) <= int(level) {
+		return nil, fmt.Errorf("unknown label %d", level)
+	}
+	return &v.ctrls[len(v.ctrls)-1-int(level)], nil
+}
+
+func (v *funcValidator) blockType(bt tbinary.BlockType) ([]tbinary.ValueType, []tbinary.ValueType, error) {
+	switch bt := bt.(type) {
+	case tbinary.BlockTypeVoid:
+		return nil, nil, nil
+	case tbinary.BlockTypeValue:
+		return nil, bt.ValueTypes, nil
+	case tbinary.BlockTypeIndex:
+		if len(v.types) <= int(bt.Index) {
+			return nil, nil, fmt.Errorf("unknown type %d", bt.Index)
+		}
+		return v.types[bt.Index].Params, v.types[bt.Index].Results, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported block type: %T", bt)
+	}
+}
+
+// apply pops the params and pushes the results of s.
+func (v *funcValidator) apply(s signature) error {
+	if _, err := v.popVals(s.params); err != nil {
+		return err
+	}
+	v.pushVals(s.results)
+	return nil
+}
+
+// local returns the type of a local without expanding the local declarations, which may be huge.
+func (v *funcValidator) local(index uint32) (tbinary.ValueType, error) {
+	if int(index) < len(v.params) {
+		return v.params[index], nil
+	}
+	rest := uint64(index) - uint64(len(v.params))
+	for _, local := range v.locals {
+		if rest < uint64(local.TypeCount) {
+			return local.ValueType, nil
+		}
+		rest -= uint64(local.TypeCount)
+	}
+	return unknown, fmt.Errorf("unknown local %d", index)
+}
+
+func (v *funcValidator) table(index uint32) (tbinary.TableType, error) {
+	if len(v.tables) <= int(index) {
+		return tbinary.TableType{}, fmt.Errorf("unknown table %d", index)
+	}
+	return v.tables[index], nil
+}
+
+func (v *funcValidator) memory(index uint32) error {
+	if len(v.mems) <= int(index) {
+		return fmt.Errorf("unknown memory %d", index)
+	}
+	return nil
+}
+
+func (v *funcValidator) data(index uint32) error {
+	if v.datas < 0 {
+		return fmt.Errorf("data count section required")
+	}
+	if v.datas <= int(index) {
+		return fmt.Errorf("unknown data segment %d", index)
+	}
+	return nil
+}
+
+func (v *funcValidator) elem(index uint32) (tbinary.RefType, error) {
+	if len(v.elems) <= int(index) {
+		return 0, fmt.Errorf("unknown element segment %d", index)
+	}
+	return v.elems[index], nil
+}
+
+// memoryAccess checks the memory and the alignment of a load or store of width bytes.
+func (v *funcValidator) memoryAccess(memory, align, width uint32) error {
+	if err := v.memory(memory); err != nil {
+		return err
+	}
+	if 32 <= align || width < 1<<align {
+		return fmt.Errorf("alignment must not be larger than natural")
+	}
+	return nil
+}
+
+func (v *funcValidator) load(memory, align, width uint32, t tbinary.ValueType) error {
+	if err := v.memoryAccess(memory, align, width); err != nil {
+		return err
+	}
+	return v.apply(sig([]tbinary.ValueType{i32}, t))
+}
+
+func (v *funcValidator) store(memory, align, width uint32, t tbinary.ValueType) error {
+	if err := v.memoryAccess(memory, align, width); err != nil {
+		return err
+	}
+	_, err := v.popVals([]tbinary.ValueType{i32, t})
+	return err
+}
+
+func (v *funcValidator) validate(inst tbinary.Instruction) error {
+	if s, ok := numeric[inst.Opcode()]; ok {
+		return v.apply(s)
+	}
+
+	switch inst := inst.(type) {
+	case *instruction.Unreachable:
+		v.setUnreachable()
+	case *instruction.Nop:
+	case *instruction.Block:
+		return v.block(opcode.OpcodeBlock, inst.Block.BlockType)
+	case *instruction.Loop:
+		return v.block(opcode.OpcodeLoop, inst.Block.BlockType)
+	case *instruction.If:
+		if _, err := v.popExpected(i32); err != nil {
+			return err
+		}
+		return v.block(opcode.OpcodeIf, inst.Block.BlockType)
+	case *instruction.Else:
+		frame, err := v.popCtrl()
+		if err != nil {
+			return err
+		}
+		if frame.opcode != opcode.OpcodeIf {
+			return fmt.Errorf("else without if")
+		}
+		v.pushCtrl(opcode.OpcodeElse, frame.params, frame.results)
+	case *instruction.End:
+		frame, err := v.popCtrl()
+		if err != nil {
+			return err
+		}
+		// NOTE: an if without else leaves its params as results, so they must have the same types.
+		if frame.opcode == opcode.OpcodeIf && !slices.Equal(frame.params, frame.results) {
+			return fmt.Errorf("%w: if without else must not change the operand types", errTypeMismatch)
+		}
+		v.pushVals(frame.results)
+	case *instruction.Br:
+		frame, err := v.label(inst.Level)
+		if err != nil {
+			return err
+		}
+		if _, err := v.popVals(frame.labelTypes()); err != nil {
+			return err
+		}
+		v.setUnreachable()
+	case *instruction.BrIf:
+		if _, err := v.popExpected(i32); err != nil {
+			return err
+		}
+		frame, err := v.label(inst.Level)
+		if err != nil {
+			return err
+		}
+		if _, err := v.popVals(frame.labelTypes()); err != nil {
+			return err
+		}
+		v.pushVals(frame.labelTypes())
+	case *instruction.BrTable:
+		return v.brTable(inst)
+	case *instruction.Return:
+		if _, err := v.popVals(v.ctrls[0].results); err != nil {
+			return err
+		}
+		v.setUnreachable()
+	case *instruction.Call:
+		if len(v.funcs) <= int(inst.Index) {
+			return fmt.Errorf("unknown function %d", inst.Index)
+		}
+		return v.apply(funcSignature(v.funcs[inst.Index]))
+	case *instruction.CallIndirect:
+		table, err := v.table(inst.TableIndex)
+		if err != nil {
+			return err
+		}
+		if table.ElementType != tbinary.RefTypeFunc {
+			return fmt.Errorf("%w: call_indirect requires a funcref table", errTypeMismatch)
+		}
+		if len(v.types) <= int(inst.TypeIndex) {
+			return fmt.Errorf("unknown type %d", inst.TypeIndex)
+		}
+		if _, err := v.popExpected(i32); err != nil {
+			return err
+		}
+		return v.apply(funcSignature(v.types[inst.TypeIndex]))
+	case *instruction.Drop:
+		_, err := v.popVal()
+		return err
+	case *instruction.TypedSelect:
+		if len(inst.Types) != 1 {
+			return fmt.Errorf("invalid result arity of select: %d", len(inst.Types))
+		}
+		t := inst.Types[0]
+		if _, err := v.popVals([]tbinary.ValueType{t, t, i32}); err != nil {
+			return err
+		}
+		v.pushVal(t)
+	case *instruction.Select:
+		return v.selectUntyped()
+	case *instruction.LocalGet:
+		t, err := v.local(inst.Index)
+		if err != nil {
+			return err
+		}
+		v.pushVal(t)
+	case *instruction.LocalSet:
+		t, err := v.local(inst.Index)
+		if err != nil {
+			return err
+		}
+		_, err = v.popExpected(t)
+		return err
+	case *instruction.GlobalGet:
+		if len(v.globals) <= int(inst.Index) {
+			return fmt.Errorf("unknown global %d", inst.Index)
+		}
+		v.pushVal(v.globals[inst.Index].ValueType)
+	case *instruction.GlobalSet:
+		if len(v.globals) <= int(inst.Index) {
+			return fmt.Errorf("unknown global %d", inst.Index)
+		}
+		if !v.globals[inst.Index].Mutable {
+			return fmt.Errorf("global %d is immutable", inst.Index)
+		}
+		_, err := v.popExpected(v.globals[inst.Index].ValueType)
+		return err
+	case *instruction.TableGet:
+		table, err := v.table(inst.TableIndex)
+		if err != nil {
+			return err
+		}
+		return v.apply(sig([]tbinary.ValueType{i32}, tbinary.ValueType(table.ElementType)))
+	case *instruction.TableSet:
+		table, err := v.table(inst.TableIndex)
+		if err != nil {
+			return err
+		}
+		_, err = v.popVals([]tbinary.ValueType{i32, tbinary.ValueType(table.ElementType)})
+		return err
+	case *instruction.I32Load:
+		return v.load(inst.MemoryIndex, inst.Align, 4, i32)
+	case *instruction.I64Load:
+		return v.load(inst.MemoryIndex, inst.Align, 8, i64)
+	case *instruction.F32Load:
+		return v.load(inst.MemoryIndex, inst.Align, 4, f32)
+	case *instruction.F64Load:
+		return v.load(inst.MemoryIndex, inst.Align, 8, f64)
+	case *instruction.I32Load8S:
+		return v.load(inst.MemoryIndex, inst.Align, 1, i32)
+	case *instruction.I32Load8U:
+		return v.load(inst.MemoryIndex, inst.Align, 1, i32)
+	case *instruction.I32Load16S:
+		return v.load(inst.MemoryIndex, inst.Align, 2, i32)
+	case *instruction.I32Load16U:
+		return v.load(inst.MemoryIndex, inst.Align, 2, i32)
+	case *instruction.I64Load8S:
+		return v.load(inst.MemoryIndex, inst.Align, 1, i64)
+	case *instruction.I64Load8U:
+		return v.load(inst.MemoryIndex, inst.Align, 1, i64)
+	case *instruction.I64Load16S:
+		return v.load(inst.MemoryIndex, inst.Align, 2, i64)
+	case *instruction.I64Load16U:
+		return v.load(inst.MemoryIndex, inst.Align, 2, i64)
+	case *instruction.I64Load32S:
+		return v.load(inst.MemoryIndex, inst.Align, 4, i64)
+	case *instruction.I64Load32U:
+		return v.load(inst.MemoryIndex, inst.Align, 4, i64)
+	case *instruction.I32Store:
+		return v.store(inst.MemoryIndex, inst.Align, 4, i32)
+	case *instruction.I64Store:
+		return v.store(inst.MemoryIndex, inst.Align, 8, i64)
+	case *instruction.F32Store:
+		return v.store(inst.MemoryIndex, inst.Align, 4, f32)
+	case *instruction.F64Store:
+		return v.store(inst.MemoryIndex, inst.Align, 8, f64)
+	case *instruction.I32Store8:
+		return v.store(inst.MemoryIndex, inst.Align, 1, i32)
+	case *instruction.I32Store16:
+		return v.store(inst.MemoryIndex, inst.Align, 2, i32)
+	case *instruction.I64Store8:
+		return v.store(inst.MemoryIndex, inst.Align, 1, i64)
+	case *instruction.I64Store16:
+		return v.store(inst.MemoryIndex, inst.Align, 2, i64)
+	case *instruction.I64Store32:
+		return v.store(inst.MemoryIndex, inst.Align, 4, i64)
+	case *instruction.MemorySize:
+		if err := v.memory(inst.MemoryIndex); err != nil {
+			return err
+		}
+		v.pushVal(i32)
+	case *instruction.MemoryGrow:
+		if err := v.memory(inst.MemoryIndex); err != nil {
+			return err
+		}
+		return v.apply(sig([]tbinary.ValueType{i32}, i32))
+	case *instruction.I32Const:
+		v.pushVal(i32)
+	case *instruction.I64Const:
+		v.pushVal(i64)
+	case *instruction.F32Const:
+		v.pushVal(f32)
+	case *instruction.F64Const:
+		v.pushVal(f64)
+	case *instruction.RefNull:
+		v.pushVal(tbinary.ValueType(inst.Type))
+	case *instruction.RefIsNull:
+		if _, err := v.popRef(); err != nil {
+			return err
+		}
+		v.pushVal(i32)
+	case *instruction.RefFunc:
+		if len(v.funcs) <= int(inst.Index) {
+			return fmt.Errorf("unknown function %d", inst.Index)
+		}
+		if !v.refs[inst.Index] {
+			return fmt.Errorf("undeclared function reference %d", inst.Index)
+		}
+		v.pushVal(tbinary.ValueTypeFuncRef)
+	case *instruction.FCPrefix:
+		return v.validateFC(inst.FC)
+	default:
+		return fmt.Errorf("unsupported instruction: %T", inst)
+	}
+
+	return nil
+}
+
+func (v *funcValidator) block(op opcode.Opcode, bt tbinary.BlockType) error {
+	params, results, err := v.blockType(bt)
+	if err != nil {
+		return err
+	}
+	if _, err := v.popVals(params); err != nil {
+		return err
+	}
+	v.pushCtrl(op, params, results)
+	return nil
+}
+
+func (v *funcValidator) brTable(inst *instruction.BrTable) error {
+	if _, err := v.popExpected(i32); err != nil {
+		return err
+	}
+	def, err := v.label(inst.Default)
+	if err != nil {
+		return err
+	}
+	arity := len(def.labelTypes())
+	for _, level := range inst.Levels {
+		frame, err := v.label(level)
+		if err != nil {
+			return err
+		}
+		if len(frame.labelTypes()) != arity {
+			return fmt.Errorf("%w: br_table targets have inconsistent arities", errTypeMismatch)
+		}
+		types, err := v.popVals(frame.labelTypes())
+		if err != nil {
+			return err
+		}
+		v.pushVals(types)
+	}
+	if _, err := v.popVals(def.labelTypes()); err != nil {
+		return err
+	}
+	v.setUnreachable()
+	return nil
+}
+
+func (v *funcValidator) selectUntyped() error {
+	if _, err := v.popExpected(i32); err != nil {
+		return err
+	}
+	t1, err := v.popVal()
+	if err != nil {
+		return err
+	}
+	t2, err := v.popVal()
+	if err != nil {
+		return err
+	}
+	if isRef(t1) || isRef(t2) {
+		return fmt.Errorf("%w: select without a type requires numeric operands", errTypeMismatch)
+	}
+	if t1 != t2 && t1 != unknown && t2 != unknown {
+		return fmt.Errorf("%w: select operands have different types %s and %s", errTypeMismatch, typeName(t1), typeName(t2))
+	}
+	if t1 == unknown {
+		t1 = t2
+	}
+	v.pushVal(t1)
+	return nil
+}
+
+func (v *funcValidator) validateFC(inst instruction.FC) error {
+	if s, ok := saturating[inst.Opcode()]; ok {
+		return v.apply(s)
+	}
+
+	i32x3 := []tbinary.ValueType{i32, i32, i32}
+	switch inst := inst.(type) {
+	case *instruction.FCMemoryInit:
+		if err := v.memory(inst.MemoryIndex); err != nil {
+			return err
+		}
+		if err := v.data(inst.DataIndex); err != nil {
+			return err
+		}
+		_, err := v.popVals(i32x3)
+		return err
+	case *instruction.FCDataDrop:
+		return v.data(inst.DataIndex)
+	case *instruction.FCMemoryCopy:
+		if err := v.memory(inst.DstMemoryIndex); err != nil {
+			return err
+		}
+		if err := v.memory(inst.SrcMemoryIndex); err != nil {
+			return err
+		}
+		_, err := v.popVals(i32x3)
+		return err
+	case *instruction.FCMemoryFill:
+		if err := v.memory(inst.MemoryIndex); err != nil {
+			return err
+		}
+		_, err := v.popVals(i32x3)
+		return err
+	case *instruction.FCTableInit:
+		table, err := v.table(inst.TableIndex)
+		if err != nil {
+			return err
+		}
+		elem, err := v.elem(inst.ElementIndex)
+		if err != nil {
+			return err
+		}
+		if table.ElementType != elem {
+			return fmt.Errorf("%w: element segment %d does not match table %d", errTypeMismatch, inst.ElementIndex, inst.TableIndex)
+		}
+		_, err = v.popVals(i32x3)
+		return err
+	case *instruction.FCElemDrop:
+		_, err := v.elem(inst.ElementIndex)
+		return err
+	case *instruction.FCTableCopy:
+		dst, err := v.table(inst.DstTableIndex)
+		if err != nil {
+			return err
+		}
+		src, err := v.table(inst.SrcTableIndex)
+		if err != nil {
+			return err
+		}
+		if dst.ElementType != src.ElementType {
+			return fmt.Errorf("%w: table %d does not match table %d", errTypeMismatch, inst.SrcTableIndex, inst.DstTableIndex)
+		}
+		_, err = v.popVals(i32x3)
+		return err
+	case *instruction.FCTableGrow:
+		table, err := v.table(inst.TableIndex)
+		if err != nil {
+			return err
+		}
+		return v.apply(sig([]tbinary.ValueType{tbinary.ValueType(table.ElementType), i32}, i32))
+	case *instruction.FCTableSize:
+		if _, err := v.table(inst.TableIndex); err != nil {
+			return err
+		}
+		v.pushVal(i32)
+	case *instruction.FCTableFill:
+		table, err := v.table(inst.TableIndex)
+		if err != nil {
+			return err
+		}
+		_, err = v.popVals([]tbinary.ValueType{i32, tbinary.ValueType(table.ElementType), i32})
+		return err
+	default:
+		return fmt.Errorf("unsupported instruction: %T", inst)
+	}
+
+	return nil
+}
+
+func isRef(t tbinary.ValueType) bool {
+	return t == tbinary.ValueTypeFuncRef || t == tbinary.ValueTypeExternRef
+}
@@ -0,0 +1,93 @@
+package validation
+
+import (
+	"github.com/Warashi/wasmium/opcode"
+	tbinary "github.com/Warashi/wasmium/types/binary"
+)
+
+// signature is the operand and result types of an instruction without immediates.
+type signature struct {
+	params  []tbinary.ValueType
+	results []tbinary.ValueType
+}
+
+var (
+	i32 = tbinary.ValueTypeI32
+	i64 = tbinary.ValueTypeI64
+	f32 = tbinary.ValueTypeF32
+	f64 = tbinary.ValueTypeF64
+)
+
+func sig(params []tbinary.ValueType, result tbinary.ValueType) signature {
+	return signature{params: params, results: []tbinary.ValueType{result}}
+}
+
+func funcSignature(ft tbinary.FuncType) signature {
+	return signature{params: ft.Params, results: ft.Results}
+}
+
+// numeric maps the numeric instructions to their signatures.
+var numeric = func() map[opcode.Opcode]signature {
+	m := make(map[opcode.Opcode]signature)
+	span := func(first, last opcode.Opcode, s signature) {
+		for op := first; op <= last; op++ {
+			m[op] = s
+		}
+	}
+
+	unop := func(t tbinary.ValueType) signature { return sig([]tbinary.ValueType{t}, t) }
+	binop := func(t tbinary.ValueType) signature { return sig([]tbinary.ValueType{t, t}, t) }
+	test := func(t tbinary.ValueType) signature { return sig([]tbinary.ValueType{t}, i32) }
+	compare := func(t tbinary.ValueType) signature { return sig([]tbinary.ValueType{t, t}, i32) }
+	convert := func(from, to tbinary.ValueType) signature { return sig([]tbinary.ValueType{from}, to) }
+
+	m[opcode.OpcodeI32Eqz] = test(i32)
+	span(opcode.OpcodeI32Eq, opcode.OpcodeI32GeU, compare(i32))
+	m[opcode.OpcodeI64Eqz] = test(i64)
+	span(opcode.OpcodeI64Eq, opcode.OpcodeI64GeU, compare(i64))
+	span(opcode.OpcodeF32Eq, opcode.OpcodeF32Ge, compare(f32))
+	span(opcode.OpcodeF64Eq, opcode.OpcodeF64Ge, compare(f64))
+
+	span(opcode.OpcodeI32Clz, opcode.OpcodeI32Popcnt, unop(i32))
+	span(opcode.OpcodeI32Add, opcode.OpcodeI32Rotr, binop(i32))
+	span(opcode.OpcodeI64Clz, opcode.OpcodeI64Popcnt, unop(i64))
+	span(opcode.OpcodeI64Add, opcode.OpcodeI64Rotr, binop(i64))
+	span(opcode.OpcodeF32Abs, opcode.OpcodeF32Sqrt, unop(f32))
+	span(opcode.OpcodeF32Add, opcode.OpcodeF32Copysign, binop(f32))
+	span(opcode.OpcodeF64Abs, opcode.OpcodeF64Sqrt, unop(f64))
+	span(opcode.OpcodeF64Add, opcode.OpcodeF64Copysign, binop(f64))
+
+	m[opcode.OpcodeI32WrapI64] = convert(i64, i32)
+	span(opcode.OpcodeI32TruncF32S, opcode.OpcodeI32TruncF32U, convert(f32, i32))
+	span(opcode.OpcodeI32TruncF64S, opcode.OpcodeI32TruncF64U, convert(f64, i32))
+	span(opcode.OpcodeI64ExtendI32S, opcode.OpcodeI64ExtendI32U, convert(i32, i64))
+	span(opcode.OpcodeI64TruncF32S, opcode.OpcodeI64TruncF32U, convert(f32, i64))
+	span(opcode.OpcodeI64TruncF64S, opcode.OpcodeI64TruncF64U, convert(f64, i64))
+	span(opcode.OpcodeF32ConvertI32S, opcode.OpcodeF32ConvertI32U, convert(i32, f32))
+	span(opcode.OpcodeF32ConvertI64S, opcode.OpcodeF32ConvertI64U, convert(i64, f32))
+	m[opcode.OpcodeF32DemoteF64] = convert(f64, f32)
+	span(opcode.OpcodeF64ConvertI32S, opcode.OpcodeF64ConvertI32U, convert(i32, f64))
+	span(opcode.OpcodeF64ConvertI64S, opcode.OpcodeF64ConvertI64U, convert(i64, f64))
+	m[opcode.OpcodeF64PromoteF32] = convert(f32, f64)
+	m[opcode.OpcodeI32ReinterpretF32] = convert(f32, i32)
+	m[opcode.OpcodeI64ReinterpretF64] = convert(f64, i64)
+	m[opcode.OpcodeF32ReinterpretI32] = convert(i32, f32)
+	m[opcode.OpcodeF64ReinterpretI64] = convert(i64, f64)
+
+	span(opcode.OpcodeI32Extend8S, opcode.OpcodeI32Extend16S, unop(i32))
+	span(opcode.OpcodeI64Extend8S, opcode.OpcodeI64Extend32S, unop(i64))
+
+	return m
+}()
+
+// saturating maps the saturating truncation instructions to their signatures.
+var saturating = map[opcode.OpcodeFC]signature{
+	opcode.OpcodeFCI32TruncSatF32S: sig([]tbinary.ValueType{f32}, i32),
+	opcode.OpcodeFCI32TruncSatF32U: sig([]tbinary.ValueType{f32}, i32),
+	opcode.OpcodeFCI32TruncSatF64S: sig([]tbinary.ValueType{f64}, i32),
+	opcode.OpcodeFCI32TruncSatF64U: sig([]tbinary.ValueType{f64}, i32),
+	opcode.OpcodeFCI64TruncSatF32S: sig([]tbinary.ValueType{f32}, i64),
+	opcode.OpcodeFCI64TruncSatF32U: sig([]tbinary.ValueType{f32}, i64),
+	opcode.OpcodeFCI64TruncSatF64S: sig([]tbinary.ValueType{f64}, i64),
+	opcode.OpcodeFCI64TruncSatF64U: sig([]tbinary.ValueType{f64}, i64),
+}
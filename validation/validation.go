@@ -0,0 +1,334 @@
+// Package validation checks that a decoded module is valid before it is executed.
+// It implements the validation algorithm of the WebAssembly specification.
+package validation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Warashi/wasmium/binary"
+	tbinary "github.com/Warashi/wasmium/types/binary"
+)
+
+// ErrInvalid is wrapped by every error returned by Validate.
+var ErrInvalid = errors.New("invalid module")
+
+// maxPages is the maximum number of pages of a memory.
+const maxPages = 65536
+
+// maxLocals is the implementation limit on the number of locals of a function, including its parameters.
+// The specification allows up to 2^32-1, but the locals are allocated on every call.
+const maxLocals = 50000
+
+// Error is a validation error in a function body.
+type Error struct {
+	// FuncIndex is the index of the function in the function index space of the module.
+	FuncIndex uint32
+	// Offset is the index of the invalid instruction in the function body.
+	Offset int
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("func[%d]+%d: %v", e.FuncIndex, e.Offset, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// context holds the entities of a module visible to the validation of its parts.
+type context struct {
+	types   []tbinary.FuncType
+	funcs   []tbinary.FuncType
+	tables  []tbinary.TableType
+	mems    []tbinary.Limits
+	globals []tbinary.GlobalType
+	elems   []tbinary.RefType
+	// datas is the number of data segments, or -1 if the module has no data count section.
+	datas int
+	// refs are the functions that may be referenced by ref.func in function bodies.
+	refs map[uint32]bool
+	// importedGlobals is the number of imported globals, which are the only globals const expressions may read.
+	importedGlobals int
+}
+
+// Validate checks that m is valid.
+// The returned error wraps ErrInvalid, and an *Error when a function body is invalid.
+func Validate(m *binary.Module) error {
+	if err := validate(m); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return nil
+}
+
+func validate(m *binary.Module) error {
+	c, err := newContext(m)
+	if err != nil {
+		return err
+	}
+
+	for i, global := range m.GlobalSection() {
+		if err := c.validateConstExpr(global.InitExpr, global.Type.ValueType); err != nil {
+			return fmt.Errorf("global %d: %w", i, err)
+		}
+	}
+
+	for i, elem := range m.ElementSection() {
+		if err := c.validateElement(elem); err != nil {
+			return fmt.Errorf("element segment %d: %w", i, err)
+		}
+	}
+
+	for i, data := range m.DataSection() {
+		if data.Mode != tbinary.DataModeActive {
+			continue
+		}
+		if len(c.mems) <= int(data.MemoryIndex) {
+			return fmt.Errorf("data segment %d: unknown memory %d", i, data.MemoryIndex)
+		}
+		if err := c.validateConstExpr(data.Offset, tbinary.ValueTypeI32); err != nil {
+			return fmt.Errorf("data segment %d: %w", i, err)
+		}
+	}
+
+	if start, ok := m.Start(); ok {
+		if len(c.funcs) <= int(start) {
+			return fmt.Errorf("unknown function %d", start)
+		}
+		if ft := c.funcs[start]; len(ft.Params) != 0 || len(ft.Results) != 0 {
+			return fmt.Errorf("start function %d must have type [] -> []", start)
+		}
+	}
+
+	if err := c.validateExports(m.ExportSection()); err != nil {
+		return err
+	}
+
+	imported := len(c.funcs) - len(m.FunctionSection())
+	for i, body := range m.CodeSection() {
+		index := uint32(imported + i)
+		if err := c.validateFunc(index, c.funcs[index], body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newContext collects the entities of m in their index spaces, checking their types.
+func newContext(m *binary.Module) (*context, error) {
+	c := &context{
+		types: m.TypeSection(),
+		datas: -1,
+		refs:  make(map[uint32]bool),
+	}
+	if count, ok := m.DataCount(); ok {
+		c.datas = int(count)
+	}
+
+	for _, impt := range m.ImportSection() {
+		switch desc := impt.Desc.(type) {
+		case tbinary.ImportDescFunc:
+			if len(c.types) <= int(desc.Index) {
+				return nil, fmt.Errorf("import %s.%s: unknown type %d", impt.Module, impt.Field, desc.Index)
+			}
+			c.funcs = append(c.funcs, c.types[desc.Index])
+		case tbinary.ImportDescTable:
+			if err := validateLimits(desc.Type.Limits, 1<<32-1); err != nil {
+				return nil, fmt.Errorf("import %s.%s: %w", impt.Module, impt.Field, err)
+			}
+			c.tables = append(c.tables, desc.Type)
+		case tbinary.ImportDescMemory:
+			if err := validateLimits(desc.Memory.Limits, maxPages); err != nil {
+				return nil, fmt.Errorf("import %s.%s: %w", impt.Module, impt.Field, err)
+			}
+			c.mems = append(c.mems, desc.Memory.Limits)
+		case tbinary.ImportDescGlobal:
+			c.globals = append(c.globals, desc.Type)
+		}
+	}
+	c.importedGlobals = len(c.globals)
+
+	if len(m.FunctionSection()) != len(m.CodeSection()) {
+		return nil, fmt.Errorf("function and code section have inconsistent lengths: %d != %d", len(m.FunctionSection()), len(m.CodeSection()))
+	}
+	for i, typeIdx := range m.FunctionSection() {
+		if len(c.types) <= int(typeIdx) {
+			return nil, fmt.Errorf("function %d: unknown type %d", len(c.funcs)+i, typeIdx)
+		}
+		c.funcs = append(c.funcs, c.types[typeIdx])
+	}
+
+	for i, table := range m.TableSection() {
+		if err := validateLimits(table.Limits, 1<<32-1); err != nil {
+			return nil, fmt.Errorf("table %d: %w", len(c.tables)+i, err)
+		}
+		c.tables = append(c.tables, table)
+	}
+
+	for i, mem := range m.MemorySection() {
+		if err := validateLimits(mem.Limits, maxPages); err != nil {
+			return nil, fmt.Errorf("memory %d: %w", len(c.mems)+i, err)
+		}
+		c.mems = append(c.mems, mem.Limits)
+	}
+
+	for _, global := range m.GlobalSection() {
+		c.globals = append(c.globals, global.Type)
+	}
+
+	for _, elem := range m.ElementSection() {
+		c.elems = append(c.elems, elem.Type)
+	}
+
+	// NOTE: functions referenced outside of function bodies are declared for ref.func.
+	declare := func(expr tbinary.Expr) {
+		if f, ok := expr.(tbinary.ExprRefFunc); ok {
+			c.refs[uint32(f)] = true
+		}
+	}
+	for _, global := range m.GlobalSection() {
+		declare(global.InitExpr)
+	}
+	for _, elem := range m.ElementSection() {
+		for _, expr := range elem.Init {
+			declare(expr)
+		}
+	}
+	for _, export := range m.ExportSection() {
+		if desc, ok := export.Desc.(tbinary.ExportDescFunc); ok {
+			c.refs[desc.Index] = true
+		}
+	}
+
+	return c, nil
+}
+
+func validateLimits(limits tbinary.Limits, bound uint64) error {
+	if bound < uint64(limits.Min) {
+		return fmt.Errorf("size minimum must not be greater than %d", bound)
+	}
+	if !limits.HasMax {
+		return nil
+	}
+	if bound < uint64(limits.Max) {
+		return fmt.Errorf("size maximum must not be greater than %d", bound)
+	}
+	if limits.Max < limits.Min {
+		return fmt.Errorf("size minimum must not be greater than maximum")
+	}
+	return nil
+}
+
+// validateConstExpr checks that expr is a constant expression of type want.
+func (c *context) validateConstExpr(expr tbinary.Expr, want tbinary.ValueType) error {
+	got, err := c.constExprType(expr)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("type mismatch: expected %s, got %s", typeName(want), typeName(got))
+	}
+	return nil
+}
+
+func (c *context) constExprType(expr tbinary.Expr) (tbinary.ValueType, error) {
+	switch expr := expr.(type) {
+	case tbinary.ExprValueConstI32:
+		return tbinary.ValueTypeI32, nil
+	case tbinary.ExprValueConstI64:
+		return tbinary.ValueTypeI64, nil
+	case tbinary.ExprValueConstF32:
+		return tbinary.ValueTypeF32, nil
+	case tbinary.ExprValueConstF64:
+		return tbinary.ValueTypeF64, nil
+	case tbinary.ExprRefNull:
+		return tbinary.ValueType(expr), nil
+	case tbinary.ExprRefFunc:
+		if len(c.funcs) <= int(expr) {
+			return 0, fmt.Errorf("unknown function %d", expr)
+		}
+		return tbinary.ValueTypeFuncRef, nil
+	case tbinary.ExprGlobalIndex:
+		if c.importedGlobals <= int(expr) {
+			return 0, fmt.Errorf("unknown global %d", expr)
+		}
+		if c.globals[expr].Mutable {
+			return 0, fmt.Errorf("constant expression required")
+		}
+		return c.globals[expr].ValueType, nil
+	default:
+		return 0, fmt.Errorf("constant expression required")
+	}
+}
+
+func (c *context) validateElement(elem tbinary.Element) error {
+	for _, expr := range elem.Init {
+		if err := c.validateConstExpr(expr, tbinary.ValueType(elem.Type)); err != nil {
+			return err
+		}
+	}
+	if elem.Mode != tbinary.ElementModeActive {
+		return nil
+	}
+	if len(c.tables) <= int(elem.TableIndex) {
+		return fmt.Errorf("unknown table %d", elem.TableIndex)
+	}
+	if table := c.tables[elem.TableIndex]; table.ElementType != elem.Type {
+		return fmt.Errorf("type mismatch: expected %s, got %s", typeName(tbinary.ValueType(table.ElementType)), typeName(tbinary.ValueType(elem.Type)))
+	}
+	return c.validateConstExpr(elem.Offset, tbinary.ValueTypeI32)
+}
+
+func (c *context) validateExports(exports []tbinary.Export) error {
+	names := make(map[string]bool, len(exports))
+	for _, export := range exports {
+		if names[export.Name] {
+			return fmt.Errorf("duplicate export name %q", export.Name)
+		}
+		names[export.Name] = true
+
+		var (
+			kind  string
+			index uint32
+			count int
+		)
+		switch desc := export.Desc.(type) {
+		case tbinary.ExportDescFunc:
+			kind, index, count = "function", desc.Index, len(c.funcs)
+		case tbinary.ExportDescTable:
+			kind, index, count = "table", desc.Index, len(c.tables)
+		case tbinary.ExportDescMemory:
+			kind, index, count = "memory", desc.Index, len(c.mems)
+		case tbinary.ExportDescGlobal:
+			kind, index, count = "global", desc.Index, len(c.globals)
+		}
+		if count <= int(index) {
+			return fmt.Errorf("export %q: unknown %s %d", export.Name, kind, index)
+		}
+	}
+	return nil
+}
+
+func typeName(t tbinary.ValueType) string {
+	switch t {
+	case tbinary.ValueTypeI32:
+		return "i32"
+	case tbinary.ValueTypeI64:
+		return "i64"
+	case tbinary.ValueTypeF32:
+		return "f32"
+	case tbinary.ValueTypeF64:
+		return "f64"
+	case tbinary.ValueTypeFuncRef:
+		return "funcref"
+	case tbinary.ValueTypeExternRef:
+		return "externref"
+	case unknown:
+		return "unknown"
+	default:
+		return fmt.Sprintf("type(%#x)", byte(t))
+	}
+}
@@ -0,0 +1,235 @@
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Warashi/wasmium/binary"
+)
+
+// module encodes a module with the given sections, each of which is an id followed by its contents.
+func module(sections ...[]byte) []byte {
+	b := []byte("\x00asm\x01\x00\x00\x00")
+	for _, s := range sections {
+		b = append(b, s[0], byte(len(s)-1))
+		b = append(b, s[1:]...)
+	}
+	return b
+}
+
+func TestValidateTestdata(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob("../testdata/*.wasm")
+	if err != nil {
+		t.Errorf("failed to list testdata: %v", err)
+		t.FailNow()
+	}
+
+	for _, file := range files {
+		// NOTE: invalid.wasm tests that runtime.New rejects invalid modules.
+		if strings.HasPrefix(filepath.Base(file), "invalid") {
+			continue
+		}
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			t.Parallel()
+
+			b, err := os.ReadFile(file)
+			if err != nil {
+				t.Errorf("failed to load testdata: %v", err)
+				t.FailNow()
+			}
+
+			m, err := binary.NewModule(bytes.NewReader(b))
+			if err != nil {
+				t.Skipf("failed to decode module: %v", err)
+			}
+
+			if err := Validate(m); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		typeVoid  = []byte{0x01, 0x01, 0x60, 0x00, 0x00}
+		typeI32   = []byte{0x01, 0x01, 0x60, 0x00, 0x01, 0x7f}
+		funcs     = func(types ...byte) []byte { return append([]byte{0x03, byte(len(types))}, types...) }
+		emptyBody = []byte{0x02, 0x00, 0x0b}
+		codes     = func(bodies ...[]byte) []byte {
+			return append([]byte{0x0a, byte(len(bodies))}, bytes.Join(bodies, nil)...)
+		}
+		exportFunc = func(name string, index byte) []byte {
+			return append(append([]byte{byte(len(name))}, name...), 0x00, index)
+		}
+	)
+
+	tests := []struct {
+		name string
+		wasm []byte
+		want string
+		// body is set when the error is in a function body at funcIndex and offset.
+		body      bool
+		funcIndex uint32
+		offset    int
+	}{
+		{
+			name: "valid",
+			wasm: module(typeI32, funcs(0), codes([]byte{0x04, 0x00, 0x41, 0x00, 0x0b})),
+		},
+		{
+			name: "unknown type in function section",
+			wasm: module(typeVoid, funcs(5), codes(emptyBody)),
+			want: "unknown type 5",
+		},
+		{
+			name: "function and code section lengths differ",
+			wasm: module(typeVoid, funcs(0, 0), codes(emptyBody)),
+			want: "inconsistent lengths",
+		},
+		{
+			name:      "result type mismatch",
+			wasm:      module(typeI32, funcs(0, 0), codes([]byte{0x04, 0x00, 0x41, 0x00, 0x0b}, []byte{0x04, 0x00, 0x42, 0x00, 0x0b})),
+			want:      "type mismatch: expected i32, got i64",
+			body:      true,
+			funcIndex: 1,
+			offset:    1,
+		},
+		{
+			name:      "operand stack underflow",
+			wasm:      module(typeVoid, funcs(0), codes([]byte{0x03, 0x00, 0x1a, 0x0b})),
+			want:      "type mismatch",
+			body:      true,
+			funcIndex: 0,
+			offset:    0,
+		},
+		{
+			name:      "unknown label",
+			wasm:      module(typeVoid, funcs(0), codes([]byte{0x04, 0x00, 0x0c, 0x01, 0x0b})),
+			want:      "unknown label 1",
+			body:      true,
+			funcIndex: 0,
+			offset:    0,
+		},
+		{
+			name:      "unknown local",
+			wasm:      module(typeI32, funcs(0), codes([]byte{0x04, 0x00, 0x20, 0x00, 0x0b})),
+			want:      "unknown local 0",
+			body:      true,
+			funcIndex: 0,
+			offset:    0,
+		},
+		{
+			name:      "missing end",
+			wasm:      module(typeVoid, funcs(0), codes([]byte{0x04, 0x00, 0x02, 0x40, 0x0b})),
+			want:      "unexpected end of the function",
+			body:      true,
+			funcIndex: 0,
+			offset:    2,
+		},
+		{
+			name:      "alignment larger than natural",
+			wasm:      module(typeI32, funcs(0), []byte{0x05, 0x01, 0x00, 0x00}, codes([]byte{0x07, 0x00, 0x41, 0x00, 0x28, 0x03, 0x00, 0x0b})),
+			want:      "alignment must not be larger than natural",
+			body:      true,
+			funcIndex: 0,
+			offset:    1,
+		},
+		{
+			name:      "too many locals",
+			wasm:      module(typeVoid, funcs(0), codes([]byte{0x08, 0x01, 0xf0, 0xff, 0xff, 0xff, 0x0f, 0x7f, 0x0b})),
+			want:      "too many locals",
+			body:      true,
+			funcIndex: 0,
+			offset:    0,
+		},
+		{
+			name:      "unknown memory in memarg",
+			wasm:      module(typeVoid, funcs(0), []byte{0x05, 0x01, 0x00, 0x00}, codes([]byte{0x09, 0x00, 0x41, 0x00, 0x28, 0x42, 0x01, 0x00, 0x1a, 0x0b})),
+			want:      "unknown memory 1",
+			body:      true,
+			funcIndex: 0,
+			offset:    1,
+		},
+		{
+			name: "memory minimum greater than maximum",
+			wasm: module([]byte{0x05, 0x01, 0x01, 0x02, 0x01}),
+			want: "size minimum must not be greater than maximum",
+		},
+		{
+			name: "memory too large",
+			wasm: module([]byte{0x05, 0x01, 0x00, 0x81, 0x80, 0x04}),
+			want: "size minimum must not be greater than 65536",
+		},
+		{
+			name: "const expression type mismatch",
+			wasm: module([]byte{0x06, 0x01, 0x7f, 0x00, 0x42, 0x00, 0x0b}),
+			want: "global 0: type mismatch: expected i32, got i64",
+		},
+		{
+			name: "global.get of a module-defined global in a const expression",
+			wasm: module([]byte{0x06, 0x02, 0x7f, 0x00, 0x41, 0x00, 0x0b, 0x7f, 0x00, 0x23, 0x00, 0x0b}),
+			want: "global 1: unknown global 0",
+		},
+		{
+			name: "duplicate export name",
+			wasm: module(typeVoid, funcs(0), append(append([]byte{0x07, 0x02}, exportFunc("f", 0)...), exportFunc("f", 0)...), codes(emptyBody)),
+			want: `duplicate export name "f"`,
+		},
+		{
+			name: "export of unknown function",
+			wasm: module(typeVoid, funcs(0), append([]byte{0x07, 0x01}, exportFunc("f", 1)...), codes(emptyBody)),
+			want: `export "f": unknown function 1`,
+		},
+		{
+			name: "start function with results",
+			wasm: module(typeI32, funcs(0), []byte{0x08, 0x00}, codes([]byte{0x04, 0x00, 0x41, 0x00, 0x0b})),
+			want: "start function 0 must have type [] -> []",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := binary.NewModule(bytes.NewReader(tt.wasm))
+			if err != nil {
+				t.Errorf("failed to decode module: %v", err)
+				t.FailNow()
+			}
+
+			err = Validate(m)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("expected ErrInvalid, got %v", err)
+				t.FailNow()
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+
+			var verr *Error
+			if errors.As(err, &verr) != tt.body {
+				t.Errorf("unexpected function body error: %v", err)
+				t.FailNow()
+			}
+			if tt.body && (verr.FuncIndex != tt.funcIndex || verr.Offset != tt.offset) {
+				t.Errorf("expected func[%d]+%d, got func[%d]+%d", tt.funcIndex, tt.offset, verr.FuncIndex, verr.Offset)
+			}
+		})
+	}
+}
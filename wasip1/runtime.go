@@ -6,12 +6,10 @@ import (
 	"os"
 
 	runtime "github.com/Warashi/wasmium/runtime"
-	tr "github.com/Warashi/wasmium/types/runtime"
+	truntime "github.com/Warashi/wasmium/types/runtime"
 )
 
-type Runtime interface {
-	AddImport(module string, name string, fn runtime.ImportFunc)
-}
+const moduleName = "wasi_snapshot_preview1"
 
 type WasiSnapshotPreview1 struct {
 	fileTable []*os.File
@@ -27,84 +25,72 @@ func NewWasiPreview1() *WasiSnapshotPreview1 {
 	}
 }
 
-func (w *WasiSnapshotPreview1) Register(runtime Runtime) {
-	runtime.AddImport("wasi_snapshot_preview1", "fd_write", w.FdWrite)
-}
-
-func (w *WasiSnapshotPreview1) FdWrite(store *runtime.Store, args ...tr.Value) ([]tr.Value, error) {
-	fd, ok := args[0].(tr.ValueI32)
-	if !ok {
-		return nil, fmt.Errorf("unexpected type: %T", args[0])
+// Options returns the options providing the WASI functions to a module.
+func (w *WasiSnapshotPreview1) Options() []runtime.Option {
+	return []runtime.Option{
+		runtime.WithHostFunc(moduleName, "fd_write", w.FdWrite),
 	}
+}
 
-	iovs, ok := args[1].(tr.ValueI32)
-	if !ok {
-		return nil, fmt.Errorf("unexpected type: %T", args[1])
+func (w *WasiSnapshotPreview1) FdWrite(caller *runtime.Caller, fd, iovs, iovsLen, rp uint32) (int32, error) {
+	if len(w.fileTable) <= int(fd) {
+		return 0, fmt.Errorf("invalid file descriptor: %d", fd)
 	}
 
-	iovsLen, ok := args[2].(tr.ValueI32)
-	if !ok {
-		return nil, fmt.Errorf("unexpected type: %T", args[2])
-	}
+	file := w.fileTable[fd]
 
-	rp, ok := args[3].(tr.ValueI32)
+	memory, ok := caller.Memory("memory")
 	if !ok {
-		return nil, fmt.Errorf("unexpected type: %T", args[3])
-	}
-
-	if fd < 0 || len(w.fileTable) <= int(fd) {
-		return nil, fmt.Errorf("invalid file descriptor: %d", fd)
+		return 0, fmt.Errorf("memory is not exported")
 	}
-
-	file := w.fileTable[fd]
-
-	memory, err := store.Memory(0)
-	read := func(addr tr.ValueI32) (int32, error) {
+	// NOTE: guest addresses are unsigned, so they are widened to int64 before any arithmetic.
+	read := func(addr int64) (uint32, error) {
 		var buf [4]byte
-		if _, err := memory.ReadAt(buf[:], int64(addr)); err != nil {
-			return 0, err
-		}
-
-		var v int32
-		if _, err := binary.Decode(buf[:], binary.LittleEndian, &v); err != nil {
+		if _, err := memory.ReadAt(buf[:], addr); err != nil {
 			return 0, err
 		}
-
-		return v, nil
+		return binary.LittleEndian.Uint32(buf[:]), nil
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to get memory: %w", err)
-	}
 	nwritten := 0
-	for range iovsLen {
-		start, err := read(iovs)
-		iovs += 4
+	for i := range int64(iovsLen) {
+		iov := int64(iovs) + 8*i
 
-		len, err := read(iovs)
-		iovs += 4
+		start, err := read(iov)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read iovec: %w", err)
+		}
+
+		size, err := read(iov + 4)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read iovec: %w", err)
+		}
 
-		buf := make([]byte, len)
+		// the size comes from the guest, so it is checked before the buffer is allocated.
+		if int64(len(memory.Data)) < int64(start)+int64(size) {
+			return 0, fmt.Errorf("failed to read: %w", truntime.ErrMemoryOutOfBounds)
+		}
+		buf := make([]byte, size)
 
 		if _, err := memory.ReadAt(buf, int64(start)); err != nil {
-			return nil, fmt.Errorf("failed to read: %w", err)
+			return 0, fmt.Errorf("failed to read: %w", err)
 		}
 
 		n, err := file.Write(buf)
 		if err != nil {
-			return nil, fmt.Errorf("failed to write: %w", err)
+			return 0, fmt.Errorf("failed to write: %w", err)
 		}
 		nwritten += n
 	}
 
 	var buf [4]byte
-	if _, err := binary.Encode(buf[:], binary.LittleEndian, int32(nwritten)); err != nil {
-		return nil, fmt.Errorf("failed to write: %w", err)
+	if _, err := binary.Encode(buf[:], binary.LittleEndian, uint32(nwritten)); err != nil {
+		return 0, fmt.Errorf("failed to write: %w", err)
 	}
 
 	if _, err := memory.WriteAt(buf[:], int64(rp)); err != nil {
-		return nil, fmt.Errorf("failed to write: %w", err)
+		return 0, fmt.Errorf("failed to write: %w", err)
 	}
 
-	return []tr.Value{tr.ValueI32(0)}, nil
+	return 0, nil
 }
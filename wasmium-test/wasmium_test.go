@@ -4,15 +4,18 @@ import (
 	"cmp"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"unsafe"
 
 	"github.com/Warashi/wasmium/runtime"
+	typesBinary "github.com/Warashi/wasmium/types/binary"
 	typesRuntime "github.com/Warashi/wasmium/types/runtime"
 )
 
@@ -35,22 +38,37 @@ func (a Value) RuntimeValue() typesRuntime.Value {
 		return typesRuntime.ValueF32(a.f32())
 	case "f64":
 		return typesRuntime.ValueF64(a.f64())
+	case "funcref":
+		// NOTE: only null funcref can be passed from spec JSON.
+		return typesRuntime.ValueFuncRef{}
+	case "externref":
+		if a.isNull() {
+			return typesRuntime.ValueExternRef{}
+		}
+		return typesRuntime.ValueExternRef{Ref: uint32(a.i32())}
 	}
 	panic(fmt.Sprintf("unsupported value type %s", a.Type))
 }
 
+func (a Value) isNull() bool {
+	var s string
+	json.Unmarshal(a.Value, &s)
+	return s == "null"
+}
+
 func (a Value) i32() int32 {
 	var s string
 	json.Unmarshal(a.Value, &s)
-	v, _ := strconv.ParseInt(s, 10, 32)
+	// NOTE: integers are written as unsigned in spec JSON.
+	v, _ := strconv.ParseUint(s, 10, 32)
 	return int32(v)
 }
 
 func (a Value) i64() int64 {
 	var s string
 	json.Unmarshal(a.Value, &s)
-	v, _ := strconv.ParseInt(s, 10, 64)
-	return v
+	v, _ := strconv.ParseUint(s, 10, 64)
+	return int64(v)
 }
 
 func (a Value) f32() [4]byte {
@@ -67,6 +85,14 @@ func (a Value) f64() [8]byte {
 	return convert[[8]byte](v)
 }
 
+func (a Value) f32bits() uint32 {
+	return convert[uint32](a.f32())
+}
+
+func (a Value) f64bits() uint64 {
+	return convert[uint64](a.f64())
+}
+
 func (a Value) String() string {
 	switch a.Type {
 	case "i32":
@@ -78,13 +104,11 @@ func (a Value) String() string {
 		binary.Encode(buf[:], binary.LittleEndian, a.i64())
 		return fmt.Sprintf("%s(0x%x)", a.Type, buf)
 	case "f32":
-		var buf [4]byte
-		binary.Encode(buf[:], binary.LittleEndian, a.i32())
-		return fmt.Sprintf("%s(0x%x)", a.Type, buf)
+		return fmt.Sprintf("%s(0x%x)", a.Type, a.f32())
 	case "f64":
-		var buf [8]byte
-		binary.Encode(buf[:], binary.LittleEndian, a.i64())
-		return fmt.Sprintf("%s(0x%x)", a.Type, buf)
+		return fmt.Sprintf("%s(0x%x)", a.Type, a.f64())
+	case "funcref", "externref":
+		return fmt.Sprintf("%s(%s)", a.Type, a.Value)
 	}
 	panic(fmt.Sprintf("unsupported value type %s", a.Type))
 }
@@ -100,22 +124,34 @@ func mustMarshalJSON(v interface{}) json.RawMessage {
 func NewValue(v typesRuntime.Value) Value {
 	switch v := v.(type) {
 	case typesRuntime.ValueI32:
-		return Value{Type: "i32", Value: mustMarshalJSON(strconv.FormatInt(int64(v), 10))}
+		return Value{Type: "i32", Value: mustMarshalJSON(strconv.FormatUint(uint64(uint32(v)), 10))}
 	case typesRuntime.ValueI64:
-		return Value{Type: "i64", Value: mustMarshalJSON(strconv.FormatInt(int64(v), 10))}
+		return Value{Type: "i64", Value: mustMarshalJSON(strconv.FormatUint(uint64(v), 10))}
 	case typesRuntime.ValueF32:
 		return Value{Type: "f32", Value: mustMarshalJSON(strconv.FormatUint(uint64(convert[uint32](v)), 10))}
 	case typesRuntime.ValueF64:
 		return Value{Type: "f64", Value: mustMarshalJSON(strconv.FormatUint(convert[uint64](v), 10))}
+	case typesRuntime.ValueFuncRef:
+		if v.IsNull() {
+			return Value{Type: "funcref", Value: mustMarshalJSON("null")}
+		}
+		// NOTE: spec JSON does not identify non-null funcref, so only the type is reported.
+		return Value{Type: "funcref"}
+	case typesRuntime.ValueExternRef:
+		if v.IsNull() {
+			return Value{Type: "externref", Value: mustMarshalJSON("null")}
+		}
+		return Value{Type: "externref", Value: mustMarshalJSON(fmt.Sprint(v.Ref))}
 	default:
 		panic(fmt.Sprintf("unsupported value type %T", v))
 	}
 }
 
 type Action struct {
-	Type  string  `json:"type"`
-	Field string  `json:"field"`
-	Args  []Value `json:"args"`
+	Type   string  `json:"type"`
+	Module string  `json:"module,omitempty"`
+	Field  string  `json:"field"`
+	Args   []Value `json:"args"`
 }
 
 func (a Action) String() string {
@@ -130,6 +166,8 @@ type Commands struct {
 	Expected   []Expected `json:"expected,omitempty"`
 	Text       string     `json:"text,omitempty"`
 	ModuleType string     `json:"module_type,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	As         string     `json:"as,omitempty"`
 }
 
 func (c Commands) TestName() string {
@@ -156,6 +194,56 @@ func convert[T, F any](f F) T {
 	return *(*T)(unsafe.Pointer(&f))
 }
 
+// matchResults reports whether got satisfies expected.
+// Expected NaN values are given as "nan:canonical" or "nan:arithmetic"
+// because the spec does not fix the exact bit pattern of NaN results.
+func matchResults(expected, got []Result) bool {
+	if len(expected) != len(got) {
+		return false
+	}
+
+	for i := range expected {
+		if !matchResult(expected[i], got[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchResult(expected, got Result) bool {
+	if len(expected.Value) == 0 {
+		// NOTE: expected value without value matches any value of the type.
+		return expected.Type == got.Type
+	}
+
+	var s string
+	if err := json.Unmarshal(expected.Value, &s); err != nil || expected.Type != got.Type {
+		return false
+	}
+
+	switch s {
+	case "nan:canonical":
+		switch got.Type {
+		case "f32":
+			return got.f32bits()&0x7fffffff == 0x7fc00000
+		case "f64":
+			return got.f64bits()&0x7fffffffffffffff == 0x7ff8000000000000
+		}
+		return false
+	case "nan:arithmetic":
+		switch got.Type {
+		case "f32":
+			return got.f32bits()&0x7fc00000 == 0x7fc00000
+		case "f64":
+			return got.f64bits()&0x7ff8000000000000 == 0x7ff8000000000000
+		}
+		return false
+	}
+
+	return reflect.DeepEqual(expected, got)
+}
+
 func invoke(r *runtime.Runtime, a Action) ([]Result, error) {
 	field := a.Field
 	args := make([]typesRuntime.Value, len(a.Args))
@@ -215,6 +303,38 @@ func setup(t *testing.T, p string) JSONWast {
 	return wast
 }
 
+// spectest returns options providing the "spectest" module that spec tests import.
+// The options share the same instances, so they must be created once per script.
+func spectest() []runtime.Option {
+	print := func(*runtime.Caller, ...typesRuntime.Value) ([]typesRuntime.Value, error) { return nil, nil }
+
+	opts := []runtime.Option{
+		runtime.WithGlobal("spectest", "global_i32", &typesRuntime.GlobalInst{Value: typesRuntime.ValueI32(666)}),
+		runtime.WithGlobal("spectest", "global_i64", &typesRuntime.GlobalInst{Value: typesRuntime.ValueI64(666)}),
+		runtime.WithGlobal("spectest", "global_f32", &typesRuntime.GlobalInst{Value: typesRuntime.NewValueF32(666.6)}),
+		runtime.WithGlobal("spectest", "global_f64", &typesRuntime.GlobalInst{Value: typesRuntime.NewValueF64(666.6)}),
+		runtime.WithTable("spectest", "table", typesRuntime.NewTableInst(typesBinary.TableType{
+			ElementType: typesBinary.RefTypeFunc,
+			Limits:      typesBinary.Limits{Min: 10, Max: 20, HasMax: true},
+		})),
+		runtime.WithMemory("spectest", "memory", typesRuntime.NewMemoryInst(typesBinary.Limits{Min: 1, Max: 2, HasMax: true})),
+	}
+	prints := map[string][]typesBinary.ValueType{
+		"print":         nil,
+		"print_i32":     {typesBinary.ValueTypeI32},
+		"print_i64":     {typesBinary.ValueTypeI64},
+		"print_f32":     {typesBinary.ValueTypeF32},
+		"print_f64":     {typesBinary.ValueTypeF64},
+		"print_i32_f32": {typesBinary.ValueTypeI32, typesBinary.ValueTypeF32},
+		"print_f64_f64": {typesBinary.ValueTypeF64, typesBinary.ValueTypeF64},
+	}
+	for name, params := range prints {
+		opts = append(opts, runtime.WithImport("spectest", name, typesBinary.FuncType{Params: params}, print))
+	}
+
+	return opts
+}
+
 func TestWasmium(t *testing.T) {
 	t.Parallel()
 
@@ -231,27 +351,72 @@ func TestWasmium(t *testing.T) {
 			wast := setup(t, p)
 
 			var r *runtime.Runtime
+			opts := spectest()
+			linker := runtime.NewLinker()
+			named := make(map[string]*runtime.Runtime)
+
+			instantiate := func(filename string) (*runtime.Runtime, error) {
+				f, err := os.Open(filepath.Join(baseDir, filename))
+				if err != nil {
+					t.Fatalf("failed to open file %s: %v", filename, err)
+				}
+				defer f.Close()
+
+				c, err := runtime.Compile(f)
+				if err != nil {
+					return nil, err
+				}
+				return linker.Instantiate(c, opts...)
+			}
+
+			// target returns the instance an action refers to.
+			target := func(a Action) *runtime.Runtime {
+				if a.Module != "" {
+					return named[a.Module]
+				}
+				return r
+			}
 
 			for _, cmd := range wast.Commands {
 				t.Run(cmd.TestName(), func(t *testing.T) {
-					defer func() {
-						if err := recover(); err != nil {
-							t.Fatalf("panic: %v", err)
-						}
-					}()
 					switch cmd.Type {
 					case "module":
+						var err error
+						r, err = instantiate(cmd.Filename)
+						if cmd.Name != "" {
+							named[cmd.Name] = r
+						}
+						if err != nil {
+							t.Fatalf("failed to create runtime: %v", err)
+						}
+					case "register":
+						registered := r
+						if cmd.Name != "" {
+							registered = named[cmd.Name]
+						}
+						if registered == nil {
+							t.Skip("module loading failed")
+						}
+						linker.Register(cmd.As, registered)
+					case "assert_invalid":
+						// NOTE: multiple memories are allowed, as in the multi-memory proposal.
+						if cmd.Text == "multiple memories" {
+							t.Skip("multiple memories are supported")
+						}
 						f, err := os.Open(filepath.Join(baseDir, cmd.Filename))
 						if err != nil {
 							t.Fatalf("failed to open file %s: %v", cmd.Filename, err)
 						}
 						defer f.Close()
-
-						r, err = runtime.New(f)
-						if err != nil {
-							t.Fatalf("failed to create runtime: %v", err)
+						if _, err := runtime.Compile(f); err == nil {
+							t.Errorf("expected error %q, got no error", cmd.Text)
+						}
+					case "assert_unlinkable", "assert_uninstantiable":
+						if _, err := instantiate(cmd.Filename); err == nil {
+							t.Errorf("expected error %q, got no error", cmd.Text)
 						}
 					case "action":
+						r := target(cmd.Action)
 						if r == nil {
 							t.Skip("module loading failed")
 						}
@@ -259,6 +424,7 @@ func TestWasmium(t *testing.T) {
 							t.Errorf("failed to execute action: %v", err)
 						}
 					case "assert_return":
+						r := target(cmd.Action)
 						if r == nil {
 							t.Skip("module loading failed")
 						}
@@ -269,9 +435,23 @@ func TestWasmium(t *testing.T) {
 						if len(cmd.Expected) == 0 && len(got) == 0 {
 							return
 						}
-						if !reflect.DeepEqual(cmd.Expected, got) {
+						if !matchResults(cmd.Expected, got) {
 							t.Errorf("assertion failed: expected %v, got %v", cmd.Expected, got)
 						}
+					case "assert_trap", "assert_exhaustion":
+						r := target(cmd.Action)
+						if r == nil {
+							t.Skip("module loading failed")
+						}
+						_, err := action(r, cmd.Action)
+						if err == nil {
+							t.Errorf("expected trap %q, got no error", cmd.Text)
+							return
+						}
+						var trap *typesRuntime.Trap
+						if !errors.As(err, &trap) || !strings.HasPrefix(cmd.Text, trap.Code.String()) {
+							t.Errorf("expected trap %q, got %v", cmd.Text, err)
+						}
 					default:
 						t.Skip(fmt.Sprintf("type %s is not implemented yet", cmd.Type))
 					}